@@ -0,0 +1,222 @@
+package applier
+
+import (
+	"bufio"
+	"code2md/diff"
+	"code2md/dumpParser"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const diffContext = 3
+
+// placeholder matches the text code2md puts in place of redacted secrets
+// and of the lines left out of truncated files.
+var placeholder = regexp.MustCompile(`\[REDACTED:[a-z-]+\]|(?m)^… (?:1 line|[0-9]+ lines) omitted …$`)
+
+type Options struct {
+	InputFolder string
+	Yes         bool
+	In          io.Reader
+	Out         io.Writer
+}
+
+type Result struct {
+	Applied   []string
+	Declined  []string
+	Unchanged []string
+}
+
+type change struct {
+	block    dumpParser.FileBlock
+	target   string
+	existing string
+	exists   bool
+	mode     os.FileMode
+}
+
+func Apply(blocks []dumpParser.FileBlock, opts Options) (Result, error) {
+	var result Result
+
+	changes := make([]change, 0, len(blocks))
+	for _, block := range blocks {
+		target, err := resolveTarget(opts.InputFolder, block.Path)
+		if err != nil {
+			return result, fmt.Errorf("line %d: %w", block.Line, err)
+		}
+		c, err := loadChange(block, target)
+		if err != nil {
+			return result, err
+		}
+		if err := checkLossless(c); err != nil {
+			return result, fmt.Errorf("line %d: %w", block.Line, err)
+		}
+		changes = append(changes, c)
+	}
+
+	prompt := bufio.NewReader(opts.In)
+	for _, c := range changes {
+		if isUnchanged(c.existing, c.block.Content) {
+			result.Unchanged = append(result.Unchanged, c.block.Path)
+			continue
+		}
+
+		oldName := "a/" + c.block.Path
+		if !c.exists {
+			oldName = "/dev/null"
+		}
+		if _, err := io.WriteString(opts.Out, diff.Unified(oldName, "b/"+c.block.Path, c.existing, c.block.Content, diffContext)); err != nil {
+			return result, fmt.Errorf("writing diff for %s: %w", c.block.Path, err)
+		}
+
+		if !opts.Yes {
+			confirmed, err := confirm(prompt, opts.Out, c.block.Path)
+			if err != nil {
+				return result, err
+			}
+			if !confirmed {
+				result.Declined = append(result.Declined, c.block.Path)
+				continue
+			}
+		}
+
+		if err := writeChange(c); err != nil {
+			return result, err
+		}
+		result.Applied = append(result.Applied, c.block.Path)
+	}
+
+	return result, nil
+}
+
+func resolveTarget(inputFolder, path string) (string, error) {
+	if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("refusing path %q: must be relative to the input folder", path)
+	}
+
+	target := filepath.Join(inputFolder, filepath.FromSlash(path))
+	rel, err := filepath.Rel(inputFolder, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("refusing path %q: outside of input folder %s", path, inputFolder)
+	}
+	if err := checkResolvedTarget(inputFolder, target); err != nil {
+		return "", fmt.Errorf("refusing path %q: %w", path, err)
+	}
+	return target, nil
+}
+
+// checkResolvedTarget makes sure that symlinks do not lead target out of
+// inputFolder: target must not be a symlink itself, and its deepest existing
+// ancestor must resolve to a directory inside the resolved input folder.
+func checkResolvedTarget(inputFolder, target string) error {
+	if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("is a symlink")
+	}
+
+	root, err := filepath.Abs(inputFolder)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return fmt.Errorf("resolving input folder %s: %w", inputFolder, err)
+	}
+
+	ancestor := filepath.Dir(target)
+	for {
+		if _, err := os.Lstat(ancestor); err == nil || !os.IsNotExist(err) {
+			break
+		}
+		parent := filepath.Dir(ancestor)
+		if parent == ancestor {
+			break
+		}
+		ancestor = parent
+	}
+	resolved, err := filepath.Abs(ancestor)
+	if err == nil {
+		resolved, err = filepath.EvalSymlinks(resolved)
+	}
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", ancestor, err)
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("resolves outside of input folder %s through a symlink", inputFolder)
+	}
+	return nil
+}
+
+func loadChange(block dumpParser.FileBlock, target string) (change, error) {
+	c := change{block: block, target: target, mode: 0644}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return c, fmt.Errorf("stating file %s: %w", target, err)
+	}
+	if info.IsDir() {
+		return c, fmt.Errorf("refusing path %q: is a directory", block.Path)
+	}
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		return c, fmt.Errorf("reading file %s: %w", target, err)
+	}
+
+	c.existing = string(content)
+	c.exists = true
+	c.mode = info.Mode().Perm()
+	return c, nil
+}
+
+// checkLossless refuses blocks marked as elided and content with
+// redaction placeholders or truncation markers that are not in the existing
+// file, as writing it would lose code, secrets and omitted lines.
+func checkLossless(c change) error {
+	if c.block.Elided {
+		return fmt.Errorf("refusing path %q: content is elided by --outline or --strip-comments", c.block.Path)
+	}
+	for _, match := range placeholder.FindAllString(c.block.Content, -1) {
+		if !strings.Contains(c.existing, match) {
+			return fmt.Errorf("refusing path %q: contains %q from a redacted or truncated dump", c.block.Path, match)
+		}
+	}
+	return nil
+}
+
+// isUnchanged treats a missing trailing newline on disk as equal, because the
+// dump always terminates file content with a newline before the closing fence.
+func isUnchanged(existing, edited string) bool {
+	return existing == edited || (!strings.HasSuffix(existing, "\n") && existing+"\n" == edited)
+}
+
+func confirm(prompt *bufio.Reader, out io.Writer, path string) (bool, error) {
+	if _, err := fmt.Fprintf(out, "Apply changes to %s? [y/N] ", path); err != nil {
+		return false, fmt.Errorf("writing prompt: %w", err)
+	}
+
+	answer, err := prompt.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("reading confirmation: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func writeChange(c change) error {
+	if err := os.MkdirAll(filepath.Dir(c.target), 0755); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", c.target, err)
+	}
+	if err := os.WriteFile(c.target, []byte(c.block.Content), c.mode); err != nil {
+		return fmt.Errorf("writing file %s: %w", c.target, err)
+	}
+	return nil
+}
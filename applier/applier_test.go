@@ -0,0 +1,222 @@
+package applier
+
+import (
+	"bytes"
+	"code2md/dumpParser"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	return string(content)
+}
+
+func TestApply(t *testing.T) {
+	t.Run("writes changes with yes", func(t *testing.T) {
+		tempDir := t.TempDir()
+		writeFile(t, filepath.Join(tempDir, "main.go"), "package main\n")
+
+		var out bytes.Buffer
+		result, err := Apply([]dumpParser.FileBlock{
+			{Path: "main.go", Content: "package main\n\nfunc main() {}\n"},
+		}, Options{InputFolder: tempDir, Yes: true, In: strings.NewReader(""), Out: &out})
+		if err != nil {
+			t.Fatalf("Apply() error: %v", err)
+		}
+
+		if !reflect.DeepEqual(result.Applied, []string{"main.go"}) {
+			t.Errorf("Applied = %v; want [main.go]", result.Applied)
+		}
+		if got := readFile(t, filepath.Join(tempDir, "main.go")); got != "package main\n\nfunc main() {}\n" {
+			t.Errorf("file content = %q", got)
+		}
+		if !strings.Contains(out.String(), "+func main() {}") {
+			t.Errorf("output should contain diff, got: %s", out.String())
+		}
+	})
+
+	t.Run("asks for confirmation per file", func(t *testing.T) {
+		tempDir := t.TempDir()
+		writeFile(t, filepath.Join(tempDir, "a.go"), "package a\n")
+		writeFile(t, filepath.Join(tempDir, "b.go"), "package b\n")
+
+		var out bytes.Buffer
+		result, err := Apply([]dumpParser.FileBlock{
+			{Path: "a.go", Content: "package a // edited\n"},
+			{Path: "b.go", Content: "package b // edited\n"},
+		}, Options{InputFolder: tempDir, In: strings.NewReader("n\ny\n"), Out: &out})
+		if err != nil {
+			t.Fatalf("Apply() error: %v", err)
+		}
+
+		if !reflect.DeepEqual(result.Declined, []string{"a.go"}) {
+			t.Errorf("Declined = %v; want [a.go]", result.Declined)
+		}
+		if !reflect.DeepEqual(result.Applied, []string{"b.go"}) {
+			t.Errorf("Applied = %v; want [b.go]", result.Applied)
+		}
+		if got := readFile(t, filepath.Join(tempDir, "a.go")); got != "package a\n" {
+			t.Errorf("declined file should be untouched, got %q", got)
+		}
+		if !strings.Contains(out.String(), "Apply changes to a.go?") {
+			t.Errorf("output should contain prompt, got: %s", out.String())
+		}
+	})
+
+	t.Run("skips unchanged files", func(t *testing.T) {
+		tempDir := t.TempDir()
+		writeFile(t, filepath.Join(tempDir, "main.go"), "package main")
+
+		var out bytes.Buffer
+		result, err := Apply([]dumpParser.FileBlock{
+			{Path: "main.go", Content: "package main\n"},
+		}, Options{InputFolder: tempDir, In: strings.NewReader(""), Out: &out})
+		if err != nil {
+			t.Fatalf("Apply() error: %v", err)
+		}
+
+		if !reflect.DeepEqual(result.Unchanged, []string{"main.go"}) {
+			t.Errorf("Unchanged = %v; want [main.go]", result.Unchanged)
+		}
+		if out.Len() != 0 {
+			t.Errorf("no diff expected for unchanged file, got: %s", out.String())
+		}
+	})
+
+	t.Run("creates new files", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		var out bytes.Buffer
+		_, err := Apply([]dumpParser.FileBlock{
+			{Path: "pkg/new.go", Content: "package pkg\n"},
+		}, Options{InputFolder: tempDir, Yes: true, In: strings.NewReader(""), Out: &out})
+		if err != nil {
+			t.Fatalf("Apply() error: %v", err)
+		}
+
+		if got := readFile(t, filepath.Join(tempDir, "pkg", "new.go")); got != "package pkg\n" {
+			t.Errorf("file content = %q", got)
+		}
+		if !strings.Contains(out.String(), "--- /dev/null") {
+			t.Errorf("diff for new file should start from /dev/null, got: %s", out.String())
+		}
+	})
+
+	t.Run("refuses paths outside input folder", func(t *testing.T) {
+		tempDir := t.TempDir()
+		inputDir := filepath.Join(tempDir, "input")
+		writeFile(t, filepath.Join(inputDir, "main.go"), "package main\n")
+
+		for _, path := range []string{"../escape.go", "/etc/passwd", "sub/../../escape.go"} {
+			var out bytes.Buffer
+			_, err := Apply([]dumpParser.FileBlock{
+				{Path: "main.go", Content: "package changed\n"},
+				{Path: path, Content: "x\n"},
+			}, Options{InputFolder: inputDir, Yes: true, In: strings.NewReader(""), Out: &out})
+			if err == nil {
+				t.Errorf("Apply() should refuse %q", path)
+			}
+		}
+
+		if got := readFile(t, filepath.Join(inputDir, "main.go")); got != "package main\n" {
+			t.Errorf("no file should be written when a path is refused, got %q", got)
+		}
+		if _, err := os.Stat(filepath.Join(tempDir, "escape.go")); !os.IsNotExist(err) {
+			t.Error("file outside input folder should not be created")
+		}
+	})
+
+	t.Run("refuses paths leaving the input folder through symlinks", func(t *testing.T) {
+		tempDir := t.TempDir()
+		inputDir := filepath.Join(tempDir, "input")
+		outsideDir := filepath.Join(tempDir, "outside")
+		writeFile(t, filepath.Join(inputDir, "main.go"), "package main\n")
+		writeFile(t, filepath.Join(inputDir, "lib", "lib.go"), "package lib\n")
+		writeFile(t, filepath.Join(outsideDir, "o.go"), "package outside\n")
+		if err := os.Symlink(outsideDir, filepath.Join(inputDir, "ext")); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+		os.Symlink(filepath.Join(outsideDir, "o.go"), filepath.Join(inputDir, "link.go"))
+		os.Symlink("lib", filepath.Join(inputDir, "inner"))
+
+		for _, path := range []string{"ext/o.go", "ext/new/n.go", "link.go"} {
+			var out bytes.Buffer
+			_, err := Apply([]dumpParser.FileBlock{{Path: path, Content: "package changed\n"}},
+				Options{InputFolder: inputDir, Yes: true, In: strings.NewReader(""), Out: &out})
+			if err == nil {
+				t.Errorf("Apply() should refuse %q", path)
+			}
+		}
+		if got := readFile(t, filepath.Join(outsideDir, "o.go")); got != "package outside\n" {
+			t.Errorf("file outside input folder was overwritten: %q", got)
+		}
+		if _, err := os.Stat(filepath.Join(outsideDir, "new")); !os.IsNotExist(err) {
+			t.Error("directory outside input folder should not be created")
+		}
+
+		var out bytes.Buffer
+		if _, err := Apply([]dumpParser.FileBlock{{Path: "inner/lib.go", Content: "package changed\n"}},
+			Options{InputFolder: inputDir, Yes: true, In: strings.NewReader(""), Out: &out}); err != nil {
+			t.Errorf("Apply() through a symlink inside the input folder error: %v", err)
+		}
+	})
+	t.Run("refuses redaction placeholders and truncation markers", func(t *testing.T) {
+		tempDir := t.TempDir()
+		writeFile(t, filepath.Join(tempDir, "config.go"), "package config\n\nconst key = \"s3cr3t\"\n")
+		writeFile(t, filepath.Join(tempDir, "redact.go"), "package redact\n\nconst p = \"[REDACTED:jwt]\"\n")
+
+		for _, content := range []string{
+			"package config\n\nconst key = \"[REDACTED:secret-assignment]\"\n",
+			"package config\n… 1 line omitted …\nconst key = \"s3cr3t\"\n",
+			"package config\n\n… 12 lines omitted …\n",
+		} {
+			var out bytes.Buffer
+			_, err := Apply([]dumpParser.FileBlock{{Path: "config.go", Content: content}},
+				Options{InputFolder: tempDir, Yes: true, In: strings.NewReader(""), Out: &out})
+			if err == nil {
+				t.Errorf("Apply() should refuse %q", content)
+			}
+		}
+		if got := readFile(t, filepath.Join(tempDir, "config.go")); got != "package config\n\nconst key = \"s3cr3t\"\n" {
+			t.Errorf("file with placeholders was written: %q", got)
+		}
+
+		var out bytes.Buffer
+		if _, err := Apply([]dumpParser.FileBlock{{Path: "redact.go", Content: "package redact\n\nconst p = \"[REDACTED:jwt]\" // placeholder\n"}},
+			Options{InputFolder: tempDir, Yes: true, In: strings.NewReader(""), Out: &out}); err != nil {
+			t.Errorf("Apply() with a placeholder already in the file error: %v", err)
+		}
+	})
+	t.Run("refuses elided blocks", func(t *testing.T) {
+		tempDir := t.TempDir()
+		writeFile(t, filepath.Join(tempDir, "a.go"), "package a\n\nfunc A() int {\n\treturn 1\n}\n")
+
+		var out bytes.Buffer
+		_, err := Apply([]dumpParser.FileBlock{{Path: "a.go", Content: "package a\n\nfunc A() int\n", Elided: true}},
+			Options{InputFolder: tempDir, Yes: true, In: strings.NewReader(""), Out: &out})
+		if err == nil {
+			t.Error("Apply() should refuse an elided block")
+		}
+		if got := readFile(t, filepath.Join(tempDir, "a.go")); got != "package a\n\nfunc A() int {\n\treturn 1\n}\n" {
+			t.Errorf("elided block was written: %q", got)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"code2md/applier"
+	"code2md/c2mConfig"
+	"code2md/dumpParser"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type applyFlags struct {
+	inputFolder string
+	yes         bool
+	help        bool
+}
+
+func newApplyFlagSet() *c2mConfig.FlagSet {
+	fs, _ := newApplyFlags()
+	return fs
+}
+
+func newApplyFlags() (*c2mConfig.FlagSet, *applyFlags) {
+	values := &applyFlags{}
+	fs := c2mConfig.NewFlagSet("apply", "[-i <input_folder>] [--yes] <edited_markdown>")
+	fs.StringVarP(&values.inputFolder, "input", "i", ".", "Directory the dump was created from (default: current directory)")
+	fs.BoolVarP(&values.yes, "yes", "y", false, "Apply all changes without asking for confirmation")
+	fs.BoolVarP(&values.help, "help", "h", false, "Show help")
+	return fs, values
+}
+
+func runApply(args []string) error {
+	fs, values := newApplyFlags()
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if values.help {
+		displayCommandUsage(fs)
+		return nil
+	}
+	if fs.NArg() != 1 {
+		displayCommandUsage(fs)
+		return errors.New("apply expects exactly one Markdown file")
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", fs.Arg(0), err)
+	}
+	defer file.Close()
+
+	blocks, err := dumpParser.Parse(file)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", fs.Arg(0), err)
+	}
+
+	result, err := applier.Apply(blocks, applier.Options{
+		InputFolder: values.inputFolder,
+		Yes:         values.yes,
+		In:          os.Stdin,
+		Out:         os.Stdout,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%d applied, %d declined, %d unchanged\n", len(result.Applied), len(result.Declined), len(result.Unchanged))
+	return nil
+}
@@ -0,0 +1,87 @@
+package c2mConfig
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// byteUnits maps lower-case unit suffixes to their size. KB, MB, GB and TB
+// are decimal; K, M, G and T are binary like KiB, MiB, GiB and TiB.
+var byteUnits = map[string]int64{
+	"": 1, "b": 1,
+	"kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12,
+	"k": 1 << 10, "m": 1 << 20, "g": 1 << 30, "t": 1 << 40,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+}
+
+// ParseByteSize parses a number of bytes with an optional unit, such as
+// 1048576, 512KB, 1.5MiB or 2g.
+func ParseByteSize(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	end := 0
+	for end < len(trimmed) && (trimmed[end] >= '0' && trimmed[end] <= '9' || trimmed[end] == '.') {
+		end++
+	}
+	number, unit := trimmed[:end], strings.TrimSpace(trimmed[end:])
+	if number == "" {
+		return 0, fmt.Errorf("%q does not start with a number", s)
+	}
+	size, ok := byteUnits[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in %q (use B, KB, MB, GB, TB, KiB, MiB, GiB or TiB)", unit, s)
+	}
+
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if n > math.MaxInt64/size {
+			return 0, fmt.Errorf("%q is too large", s)
+		}
+		return n * size, nil
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q in %q", number, s)
+	}
+	if f*float64(size) >= math.MaxInt64 {
+		return 0, fmt.Errorf("%q is too large", s)
+	}
+	return int64(f * float64(size)), nil
+}
+
+// FormatByteSize formats n with the largest unit dividing it, binary units
+// first, e.g. 100MiB or 5MB.
+func FormatByteSize(n int64) string {
+	for _, u := range []struct {
+		name string
+		size int64
+	}{
+		{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+		{"TB", 1e12}, {"GB", 1e9}, {"MB", 1e6}, {"KB", 1e3},
+	} {
+		if n != 0 && n%u.size == 0 {
+			return strconv.FormatInt(n/u.size, 10) + u.name
+		}
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+type byteSizeValue struct {
+	value *int64
+}
+
+func (b *byteSizeValue) String() string {
+	if b.value == nil {
+		return ""
+	}
+	return FormatByteSize(*b.value)
+}
+
+func (b *byteSizeValue) Set(s string) error {
+	n, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b.value = n
+	return nil
+}
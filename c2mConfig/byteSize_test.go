@@ -0,0 +1,71 @@
+package c2mConfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr string
+	}{
+		{"1048576", 1048576, ""},
+		{"0", 0, ""},
+		{"512B", 512, ""},
+		{"10KB", 10000, ""},
+		{"10kb", 10000, ""},
+		{"100MB", 100000000, ""},
+		{"2GB", 2000000000, ""},
+		{"1KiB", 1024, ""},
+		{"100MiB", 100 << 20, ""},
+		{"1.5GiB", 3 << 29, ""},
+		{"2M", 2 << 20, ""},
+		{" 4 mib ", 4 << 20, ""},
+		{"", 0, `"" does not start with a number`},
+		{"MB", 0, `"MB" does not start with a number`},
+		{"-1", 0, `"-1" does not start with a number`},
+		{"10XB", 0, `unknown unit "XB" in "10XB"`},
+		{"1.2.3MB", 0, `invalid number "1.2.3" in "1.2.3MB"`},
+		{"9000000TiB", 0, `"9000000TiB" is too large`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseByteSize(%q) error = %v; want it to contain %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseByteSize(%q) = %d, %v; want %d", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		input int64
+		want  string
+	}{
+		{0, "0"},
+		{1000, "1KB"},
+		{1024, "1KiB"},
+		{100 << 20, "100MiB"},
+		{5000000, "5MB"},
+		{1234, "1234"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatByteSize(tt.input); got != tt.want {
+			t.Errorf("FormatByteSize(%d) = %q; want %q", tt.input, got, tt.want)
+		}
+		if parsed, err := ParseByteSize(FormatByteSize(tt.input)); err != nil || parsed != tt.input {
+			t.Errorf("ParseByteSize(FormatByteSize(%d)) = %d, %v", tt.input, parsed, err)
+		}
+	}
+}
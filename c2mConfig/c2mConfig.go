@@ -3,7 +3,8 @@ package c2mConfig
 import (
 	"bufio"
 	"code2md/language"
-	"flag"
+	"code2md/sensitiveFiles"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,47 +16,152 @@ const (
 )
 
 type Config struct {
-	InputFolder      string
-	OutputMarkdown   string
-	AllowedLanguages map[string]bool
-	AllowedFileNames map[string]bool
-	IgnorePatterns   []string
-	MaxFileSize      int64
-	Help             bool
-	Version          bool
+	InputFolder       string
+	OutputMarkdown    string
+	AllowedLanguages  map[string]bool
+	AllowedFileNames  map[string]bool
+	IgnorePatterns    []string
+	MaxFileSize       int64
+	MaxLines          int
+	TailLines         int
+	MaxBytesPerFile   int64
+	Generated         string
+	StripComments     bool
+	StripLicense      bool
+	Outline           bool
+	FullPatterns      []string
+	Redact            bool
+	FailOnSecrets     bool
+	Sensitive         []string
+	Stats             bool
+	Jobs              int
+	Order             string
+	FirstPatterns     []string
+	LastPatterns      []string
+	Hidden            string
+	FollowSymlinks    bool
+	NoOutsideSymlinks bool
+	FocusFiles        []string
+	FocusDepth        int
+	Verbose           bool
+	Help              bool
+	Version           bool
+	ConfigFile        string
+	Settings          []Setting
+}
+
+type dumpFlags struct {
+	inputFolder    string
+	outputMarkdown string
+	languages      string
+	ignorePatterns string
+	maxFileSize    int64
+	maxLines       int
+	tailLines      int
+	maxBytes       int64
+	generated      string
+	stripComments  bool
+	stripLicense   bool
+	outline        bool
+	fullPatterns   string
+	redact         bool
+	failOnSecrets  bool
+	sensitive      string
+	stats          bool
+	jobs           int
+	order          string
+	first          string
+	last           string
+	hidden         bool
+	followSymlinks bool
+	noOutside      bool
+	focus          string
+	depth          int
+	configFile     string
+	profile        string
+	verbose        bool
+	help           bool
+	version        bool
+}
+
+func NewDumpFlagSet() *FlagSet {
+	fs, _ := newDumpFlagSet()
+	return fs
+}
+
+func newDumpFlagSet() (*FlagSet, *dumpFlags) {
+	values := &dumpFlags{}
+	fs := NewFlagSet("dump", "-i <input_folder> [-o <output_markdown>] [flags]")
+	fs.StringVarP(&values.inputFolder, "input", "i", "", "Input directory to scan (required)")
+	fs.StringVarP(&values.outputMarkdown, "output", "o", "", "Output Markdown file (optional, defaults to stdout)")
+	fs.StringVarP(&values.languages, "languages", "l", "", "Comma-separated list of allowed languages (extensions or names)")
+	fs.StringVarP(&values.ignorePatterns, "ignore", "I", defaultIgnoredPatterns, "Comma-separated ignore patterns")
+	fs.ByteSizeVarP(&values.maxFileSize, "max-file-size", "m", defaultMaxFileSize, "Maximum file size, in bytes or with a unit such as KB, MB or GiB (default: 100MiB)")
+	fs.IntVarP(&values.maxLines, "max-lines", "", 0, "Keep only the first lines of longer files, plus --tail-lines at the end (default: no limit)")
+	fs.IntVarP(&values.tailLines, "tail-lines", "", 0, "Number of last lines kept in files truncated by --max-lines or --max-bytes-per-file")
+	fs.ByteSizeVarP(&values.maxBytes, "max-bytes-per-file", "", 0, "Truncate files to this size, in bytes or with a unit such as KB, keeping whole lines (default: no limit)")
+	fs.ChoiceVarP(&values.generated, "generated", "", "exclude", []string{"exclude", "summarize", "include"}, "Generated and vendored files: exclude, summarize or include (default: exclude)")
+	fs.BoolVarP(&values.stripComments, "strip-comments", "", false, "Remove comments and docstrings from the output")
+	fs.BoolVarP(&values.stripLicense, "strip-license", "", false, "Remove leading license and copyright comments from the output")
+	fs.BoolVarP(&values.outline, "outline", "", false, "Render Go, Python, JavaScript/TypeScript, Java and PHP files as an outline of declarations and signatures without bodies")
+	fs.StringVarP(&values.fullPatterns, "full", "", "", "Comma-separated patterns of files kept in full with --outline")
+	fs.BoolVarP(&values.redact, "redact", "", true, "Replace private keys, tokens and other secrets with placeholders (disable with --redact=false)")
+	fs.BoolVarP(&values.failOnSecrets, "fail-on-secrets", "", false, "Exit with an error when secrets are found")
+	fs.StringVarP(&values.sensitive, "sensitive", "", sensitiveFiles.DefaultToken, "Comma-separated patterns of sensitive files that are never included; 'defaults' stands for the built-in list")
+	fs.StringVarP(&values.configFile, "config", "c", "", "Configuration file (default: .code2md.yaml or .code2md.toml in the input directory)")
+	fs.StringVarP(&values.profile, "profile", "p", "", "Named profile from the configuration file to apply")
+	fs.ChoiceVarP(&values.order, "order", "", "lexical", []string{"lexical", "dirs-first", "files-first", "size", "mtime", "git", "deps"}, "File order: lexical, dirs-first, files-first, size, mtime, git or deps (default: lexical)")
+	fs.StringVarP(&values.first, "first", "", "", "Comma-separated patterns of files written first, in pattern order")
+	fs.StringVarP(&values.last, "last", "", "", "Comma-separated patterns of files written last, in pattern order")
+	fs.BoolVarP(&values.hidden, "hidden", "", false, "Include all hidden files and directories; --no-hidden excludes all of them (default: only CI configuration such as .github)")
+	fs.BoolVarP(&values.followSymlinks, "follow-symlinks", "", false, "Follow symlinks to files and directories instead of listing them as links")
+	fs.BoolVarP(&values.noOutside, "no-outside-symlinks", "", false, "Do not follow symlinks pointing outside the input directory")
+	fs.StringVarP(&values.focus, "focus", "", "", "Comma-separated files to dump together with the local files they import")
+	fs.IntVarP(&values.depth, "depth", "", -1, "Maximum number of import levels followed from --focus files (default: unlimited)")
+	fs.IntVarP(&values.jobs, "jobs", "j", 0, "Number of files read and transformed in parallel (default: number of CPUs)")
+	fs.BoolVarP(&values.stats, "stats", "", false, "Print a summary including excluded sensitive and truncated files to stderr")
+	fs.BoolVarP(&values.verbose, "verbose", "", false, "Print the enabled languages and other details to stderr")
+	fs.BoolVarP(&values.help, "help", "h", false, "Show help")
+	fs.BoolVarP(&values.version, "version", "v", false, "Show version information")
+	return fs, values
 }
 
 func InitializeConfigFromFlags() (*Config, error) {
-	inputFolder := flag.String("input", "", "Input folder to scan")
-	outputMarkdown := flag.String("output", "", "Output Markdown file")
-	languages := flag.String("languages", "", "Comma-separated list of allowed languages (empty = use defaults)")
-	var ignorePatterns string
-	flag.StringVar(&ignorePatterns, "ignore", defaultIgnoredPatterns, "Comma-separated list of files and/or search patterns to ignore")
-	maxFileSize := flag.Int64("max-file-size", defaultMaxFileSize, "Maximum file size in bytes to process")
-	help := flag.Bool("help", false, "Show help")
-	v := flag.Bool("version", false, "Show version information")
-
-	flag.StringVar(inputFolder, "i", "", "Input folder to scan (shorthand)")
-	flag.StringVar(outputMarkdown, "o", "", "Output Markdown file (shorthand)")
-	flag.StringVar(languages, "l", "", "languages (shorthand)")
-	flag.StringVar(&ignorePatterns, "I", defaultIgnoredPatterns, "ignore patterns (shorthand)")
-	flag.Int64Var(maxFileSize, "m", defaultMaxFileSize, "max file size (shorthand)")
-	flag.BoolVar(help, "h", false, "help (shorthand)")
-	flag.BoolVar(v, "v", false, "version (shorthand)")
-
-	flag.Parse()
-
-	ignoreExplicitlySet := false
-	flag.Visit(func(f *flag.Flag) {
-		if f.Name == "ignore" || f.Name == "I" {
-			ignoreExplicitlySet = true
+	return InitializeConfigFromArgs(os.Args[1:])
+}
+
+func InitializeConfigFromArgs(args []string) (*Config, error) {
+	fs, values := newDumpFlagSet()
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	configPath, err := locateConfigFile(fs, values)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := resolveSettings(fs, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ignoreExplicitlySet := sources["ignore"] != SourceDefault
+
+	hidden := "default"
+	if sources["hidden"] != SourceDefault {
+		hidden = "exclude"
+		if values.hidden {
+			hidden = "include"
 		}
-	})
+	}
 
-	allowedLanguages := language.ParseLanguages(*languages)
+	allowedLanguages := language.ParseLanguages(values.languages)
 
 	var ignorePatternsList []string
-	for _, p := range strings.Split(ignorePatterns, ",") {
+	for _, p := range strings.Split(values.ignorePatterns, ",") {
 		trimmed := strings.TrimSpace(p)
 		if trimmed == "" {
 			continue
@@ -66,8 +172,8 @@ func InitializeConfigFromFlags() (*Config, error) {
 		ignorePatternsList = append(ignorePatternsList, trimmed)
 	}
 
-	if *outputMarkdown != "" {
-		ignorePatternsList = append(ignorePatternsList, *outputMarkdown)
+	if values.outputMarkdown != "" {
+		ignorePatternsList = append(ignorePatternsList, values.outputMarkdown)
 	}
 
 	if allowedLanguages[".css"] || allowedLanguages[".scss"] {
@@ -79,12 +185,12 @@ func InitializeConfigFromFlags() (*Config, error) {
 		return nil, err
 	}
 
-	if *inputFolder != "" && *inputFolder != "." {
-		absInput, err := filepath.Abs(*inputFolder)
+	if values.inputFolder != "" && values.inputFolder != "." {
+		absInput, err := filepath.Abs(values.inputFolder)
 		if err == nil {
 			absCwd, err := filepath.Abs(".")
 			if err == nil && absInput != absCwd {
-				inputGitignore, err := loadGitignorePatterns(filepath.Join(*inputFolder, ".gitignore"))
+				inputGitignore, err := loadGitignorePatterns(filepath.Join(values.inputFolder, ".gitignore"))
 				if err != nil {
 					return nil, err
 				}
@@ -96,17 +202,51 @@ func InitializeConfigFromFlags() (*Config, error) {
 	ignorePatternsList = append(gitignorePatterns, ignorePatternsList...)
 
 	return &Config{
-		InputFolder:      *inputFolder,
-		OutputMarkdown:   *outputMarkdown,
-		AllowedLanguages: allowedLanguages,
-		AllowedFileNames: language.GetAllowedFileNames(allowedLanguages),
-		IgnorePatterns:   ignorePatternsList,
-		MaxFileSize:      *maxFileSize,
-		Help:             *help,
-		Version:          *v,
+		InputFolder:       values.inputFolder,
+		OutputMarkdown:    values.outputMarkdown,
+		AllowedLanguages:  allowedLanguages,
+		AllowedFileNames:  language.GetAllowedFileNames(allowedLanguages),
+		IgnorePatterns:    ignorePatternsList,
+		MaxFileSize:       values.maxFileSize,
+		MaxLines:          values.maxLines,
+		TailLines:         values.tailLines,
+		MaxBytesPerFile:   values.maxBytes,
+		Generated:         values.generated,
+		StripComments:     values.stripComments,
+		StripLicense:      values.stripLicense,
+		Outline:           values.outline,
+		FullPatterns:      splitPatterns(values.fullPatterns),
+		Redact:            values.redact,
+		FailOnSecrets:     values.failOnSecrets,
+		Sensitive:         sensitiveFiles.Patterns(values.sensitive),
+		Stats:             values.stats,
+		Jobs:              values.jobs,
+		Order:             values.order,
+		FirstPatterns:     splitPatterns(values.first),
+		LastPatterns:      splitPatterns(values.last),
+		Hidden:            hidden,
+		FollowSymlinks:    values.followSymlinks,
+		NoOutsideSymlinks: values.noOutside,
+		FocusFiles:        splitPatterns(values.focus),
+		FocusDepth:        values.depth,
+		Verbose:           values.verbose,
+		Help:              values.help,
+		Version:           values.version,
+		ConfigFile:        configPath,
+		Settings:          collectSettings(fs, sources),
 	}, nil
 }
 
+func splitPatterns(list string) []string {
+	var patterns []string
+	for _, p := range strings.Split(list, ",") {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			patterns = append(patterns, trimmed)
+		}
+	}
+	return patterns
+}
+
 func isExtensionPatternForEnabledLanguage(pattern string, allowedLanguages map[string]bool) bool {
 	if !strings.HasPrefix(pattern, "*.") {
 		return false
@@ -293,3 +293,116 @@ func TestLoadGitignorePatterns(t *testing.T) {
 		}
 	})
 }
+
+func TestInitializeConfigFromArgs(t *testing.T) {
+	t.Run("parses given arguments", func(t *testing.T) {
+		tempDir := t.TempDir()
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", tempDir, "--max-file-size", "42"})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if config.InputFolder != tempDir {
+			t.Errorf("InputFolder = %q; want %q", config.InputFolder, tempDir)
+		}
+		if config.MaxFileSize != 42 {
+			t.Errorf("MaxFileSize = %d; want 42", config.MaxFileSize)
+		}
+	})
+
+	t.Run("languages relative to defaults", func(t *testing.T) {
+		tempDir := t.TempDir()
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", tempDir, "-l", "-sh,+yml", "--verbose"})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if !config.Verbose {
+			t.Error("Verbose should be set by --verbose")
+		}
+		if !config.AllowedLanguages[".go"] || !config.AllowedLanguages[".yml"] || config.AllowedLanguages[".sh"] {
+			t.Errorf("expected defaults with yml and without sh, got %v", config.AllowedLanguages)
+		}
+		if sliceContains(config.IgnorePatterns, "*.yml") {
+			t.Errorf("*.yml should be removed from default ignores when added with +yml, got %v", config.IgnorePatterns)
+		}
+	})
+
+	t.Run("byte sizes with units", func(t *testing.T) {
+		tempDir := t.TempDir()
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", tempDir, "-m", "2MiB", "--max-bytes-per-file", "1.5KB"})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if config.MaxFileSize != 2<<20 || config.MaxBytesPerFile != 1500 {
+			t.Errorf("MaxFileSize = %d, MaxBytesPerFile = %d; want %d, 1500", config.MaxFileSize, config.MaxBytesPerFile, 2<<20)
+		}
+
+		_, err = InitializeConfigFromArgs([]string{"-i", tempDir, "--max-file-size", "10XB"})
+		if err == nil || !strings.Contains(err.Error(), `unknown unit "XB" in "10XB"`) {
+			t.Errorf("InitializeConfigFromArgs() error = %v; want unknown unit", err)
+		}
+	})
+
+	t.Run("hidden files", func(t *testing.T) {
+		tempDir := t.TempDir()
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		for args, want := range map[string]string{"": "default", "--hidden": "include", "--no-hidden": "exclude"} {
+			flags := []string{"-i", tempDir}
+			if args != "" {
+				flags = append(flags, args)
+			}
+			config, err := InitializeConfigFromArgs(flags)
+			if err != nil {
+				t.Fatalf("InitializeConfigFromArgs(%v) error: %v", flags, err)
+			}
+			if config.Hidden != want {
+				t.Errorf("Hidden with %q = %q; want %q", args, config.Hidden, want)
+			}
+		}
+	})
+
+	t.Run("outline with full patterns", func(t *testing.T) {
+		tempDir := t.TempDir()
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", tempDir, "--outline", "--full", "core/**, main.go,"})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if !config.Outline {
+			t.Error("Outline should be set by --outline")
+		}
+		if len(config.FullPatterns) != 2 || config.FullPatterns[0] != "core/**" || config.FullPatterns[1] != "main.go" {
+			t.Errorf("FullPatterns = %v; want [core/** main.go]", config.FullPatterns)
+		}
+	})
+
+	t.Run("rejects unknown flags", func(t *testing.T) {
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		if _, err := InitializeConfigFromArgs([]string{"--unknown"}); err == nil {
+			t.Error("InitializeConfigFromArgs() should error for unknown flag")
+		}
+	})
+
+	t.Run("rejects positional arguments", func(t *testing.T) {
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		if _, err := InitializeConfigFromArgs([]string{"-i", ".", "extra"}); err == nil {
+			t.Error("InitializeConfigFromArgs() should error for positional arguments")
+		}
+	})
+}
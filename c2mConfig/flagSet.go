@@ -0,0 +1,197 @@
+package c2mConfig
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type FlagSet struct {
+	*flag.FlagSet
+	shorthands   map[string]string
+	longNames    map[string]string
+	order        []string
+	argsSynopsis string
+}
+
+func NewFlagSet(name, argsSynopsis string) *FlagSet {
+	fs := &FlagSet{
+		FlagSet:      flag.NewFlagSet(name, flag.ContinueOnError),
+		shorthands:   make(map[string]string),
+		longNames:    make(map[string]string),
+		argsSynopsis: argsSynopsis,
+	}
+	fs.SetOutput(io.Discard)
+	fs.FlagSet.Usage = func() {}
+	return fs
+}
+
+func (fs *FlagSet) StringVarP(p *string, name, short, value, usage string) {
+	fs.StringVar(p, name, value, usage)
+	fs.order = append(fs.order, name)
+	if short != "" {
+		fs.StringVar(p, short, value, usage)
+		fs.registerShorthand(name, short)
+	}
+}
+
+func (fs *FlagSet) BoolVarP(p *bool, name, short string, value bool, usage string) {
+	fs.BoolVar(p, name, value, usage)
+	fs.order = append(fs.order, name)
+	if short != "" {
+		fs.BoolVar(p, short, value, usage)
+		fs.registerShorthand(name, short)
+	}
+}
+
+func (fs *FlagSet) IntVarP(p *int, name, short string, value int, usage string) {
+	fs.IntVar(p, name, value, usage)
+	fs.order = append(fs.order, name)
+	if short != "" {
+		fs.IntVar(p, short, value, usage)
+		fs.registerShorthand(name, short)
+	}
+}
+
+func (fs *FlagSet) Int64VarP(p *int64, name, short string, value int64, usage string) {
+	fs.Int64Var(p, name, value, usage)
+	fs.order = append(fs.order, name)
+	if short != "" {
+		fs.Int64Var(p, short, value, usage)
+		fs.registerShorthand(name, short)
+	}
+}
+
+func (fs *FlagSet) VarP(value flag.Value, name, short, usage string) {
+	fs.Var(value, name, usage)
+	fs.order = append(fs.order, name)
+	if short != "" {
+		fs.Var(value, short, usage)
+		fs.registerShorthand(name, short)
+	}
+}
+
+// ChoiceVarP defines a string flag that only accepts one of choices.
+func (fs *FlagSet) ChoiceVarP(p *string, name, short, value string, choices []string, usage string) {
+	*p = value
+	fs.VarP(&choiceValue{value: p, choices: choices}, name, short, usage)
+}
+
+// ByteSizeVarP defines a flag for a number of bytes that accepts units such
+// as KB, MB or GiB.
+func (fs *FlagSet) ByteSizeVarP(p *int64, name, short string, value int64, usage string) {
+	*p = value
+	fs.VarP(&byteSizeValue{value: p}, name, short, usage)
+}
+
+type choiceValue struct {
+	value   *string
+	choices []string
+}
+
+func (c *choiceValue) String() string {
+	if c.value == nil {
+		return ""
+	}
+	return *c.value
+}
+
+func (c *choiceValue) Set(s string) error {
+	for _, choice := range c.choices {
+		if s == choice {
+			*c.value = s
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(c.choices, ", "))
+}
+
+// Parse parses args like flag.FlagSet.Parse and also accepts --no-<name> for
+// --<name>=false with boolean flags.
+func (fs *FlagSet) Parse(args []string) error {
+	rewritten := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			rewritten = append(rewritten, args[i:]...)
+			break
+		}
+		if name := strings.TrimLeft(arg, "-"); strings.HasPrefix(arg, "-") && strings.HasPrefix(name, "no-") && fs.Lookup(name) == nil && isBoolFlag(fs.Lookup(name[len("no-"):])) {
+			arg = "--" + name[len("no-"):] + "=false"
+		}
+		rewritten = append(rewritten, arg)
+	}
+	return fs.FlagSet.Parse(rewritten)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	if f == nil {
+		return false
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func (fs *FlagSet) registerShorthand(name, short string) {
+	fs.shorthands[name] = short
+	fs.longNames[short] = name
+}
+
+// LongName maps a shorthand to the long flag name it is an alias for.
+func (fs *FlagSet) LongName(name string) string {
+	if long, ok := fs.longNames[name]; ok {
+		return long
+	}
+	return name
+}
+
+func (fs *FlagSet) Names() []string {
+	return fs.order
+}
+
+func (fs *FlagSet) IsSet(name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if fs.LongName(f.Name) == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func (fs *FlagSet) Synopsis() string {
+	if fs.argsSynopsis == "" {
+		return "code2md " + fs.Name() + " [flags]"
+	}
+	return "code2md " + fs.Name() + " " + fs.argsSynopsis
+}
+
+// FlagTable renders the flags as the Markdown table used in the README.
+func (fs *FlagSet) FlagTable() string {
+	rows := [][3]string{{"Flag", "Short", "Description"}}
+	for _, name := range fs.order {
+		short := ""
+		if s, ok := fs.shorthands[name]; ok {
+			short = "`-" + s + "`"
+		}
+		rows = append(rows, [3]string{"`--" + name + "`", short, fs.Lookup(name).Usage})
+	}
+
+	var widths [3]int
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for i, row := range rows {
+		fmt.Fprintf(&sb, "| %-*s | %-*s | %-*s |\n", widths[0], row[0], widths[1], row[1], widths[2], row[2])
+		if i == 0 {
+			fmt.Fprintf(&sb, "| %s | %s | %s |\n", strings.Repeat("-", widths[0]), strings.Repeat("-", widths[1]), strings.Repeat("-", widths[2]))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+package c2mConfig
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlagSet(t *testing.T) {
+	newTestFlagSet := func() (*FlagSet, *string, *bool) {
+		var name string
+		var force bool
+		fs := NewFlagSet("test", "")
+		fs.StringVarP(&name, "name", "n", "", "Name to use")
+		fs.BoolVarP(&force, "force", "", false, "Force the operation")
+		return fs, &name, &force
+	}
+
+	t.Run("shorthand and long name share the value", func(t *testing.T) {
+		fs, name, _ := newTestFlagSet()
+		if err := fs.Parse([]string{"-n", "short"}); err != nil {
+			t.Fatalf("Parse() error: %v", err)
+		}
+		if *name != "short" {
+			t.Errorf("name = %q; want %q", *name, "short")
+		}
+		if !fs.IsSet("name") {
+			t.Error("IsSet(name) should be true when set via shorthand")
+		}
+		if fs.IsSet("force") {
+			t.Error("IsSet(force) should be false")
+		}
+	})
+
+	t.Run("boolean flags can be negated with no- prefix", func(t *testing.T) {
+		fs, name, force := newTestFlagSet()
+		*force = true
+		if err := fs.Parse([]string{"--no-force", "-n", "x"}); err != nil {
+			t.Fatalf("Parse() error: %v", err)
+		}
+		if *force || *name != "x" {
+			t.Errorf("force = %v, name = %q; want false, x", *force, *name)
+		}
+		if !fs.IsSet("force") {
+			t.Error("IsSet(force) should be true after --no-force")
+		}
+		if err := fs.Parse([]string{"--no-name"}); err == nil {
+			t.Error("Parse() should error for --no- of a non-boolean flag")
+		}
+	})
+
+	t.Run("parse errors are returned instead of printed", func(t *testing.T) {
+		fs, _, _ := newTestFlagSet()
+		if err := fs.Parse([]string{"--unknown"}); err == nil {
+			t.Error("Parse() should error for unknown flag")
+		}
+	})
+
+	t.Run("flag table lists long flags in definition order", func(t *testing.T) {
+		fs, _, _ := newTestFlagSet()
+		want := "| Flag      | Short | Description         |\n" +
+			"| --------- | ----- | ------------------- |\n" +
+			"| `--name`  | `-n`  | Name to use         |\n" +
+			"| `--force` |       | Force the operation |\n"
+		if got := fs.FlagTable(); got != want {
+			t.Errorf("FlagTable() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("synopsis", func(t *testing.T) {
+		fs, _, _ := newTestFlagSet()
+		if got := fs.Synopsis(); got != "code2md test [flags]" {
+			t.Errorf("Synopsis() = %q", got)
+		}
+		if got := NewDumpFlagSet().Synopsis(); !strings.HasPrefix(got, "code2md dump -i") {
+			t.Errorf("dump Synopsis() = %q", got)
+		}
+	})
+}
+
+func TestChoiceVarP(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"default", nil, "exclude", false},
+		{"valid choice", []string{"--mode", "include"}, "include", false},
+		{"invalid choice", []string{"--mode", "maybe"}, "exclude", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mode string
+			fs := NewFlagSet("test", "")
+			fs.ChoiceVarP(&mode, "mode", "", "exclude", []string{"exclude", "include"}, "Mode")
+
+			err := fs.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "must be one of exclude, include") {
+				t.Errorf("error should list the choices, got: %v", err)
+			}
+			if mode != tt.want {
+				t.Errorf("mode = %q; want %q", mode, tt.want)
+			}
+		})
+	}
+}
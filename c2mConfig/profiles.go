@@ -0,0 +1,120 @@
+package c2mConfig
+
+import (
+	"code2md/configFile"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	profilesKey = "profiles"
+	extendsKey  = "extends"
+)
+
+type profile struct {
+	name    string
+	extends string
+	node    *configFile.Node
+}
+
+func parseProfiles(fs *FlagSet, node *configFile.Node, path string) (map[string]*profile, error) {
+	profiles := make(map[string]*profile)
+	if node == nil {
+		return profiles, nil
+	}
+	if node.Kind != configFile.MapNode {
+		return nil, fmt.Errorf("%s:%d: %q must be a map of profile names to settings", path, node.Line, profilesKey)
+	}
+
+	for _, name := range node.Keys {
+		p := &profile{name: name, node: node.Fields[name]}
+		if p.node.Kind != configFile.MapNode {
+			return nil, fmt.Errorf("%s:%d: profile %q must be a map of settings", path, p.node.Line, name)
+		}
+
+		for _, key := range p.node.Keys {
+			value := p.node.Fields[key]
+			if key == extendsKey {
+				if value.Kind != configFile.ScalarNode || value.Value == "" {
+					return nil, fmt.Errorf("%s:%d: profile %q: %q must name a single profile", path, value.Line, name, extendsKey)
+				}
+				p.extends = value.Value
+				continue
+			}
+			if !isConfigurable(fs, key) || key == "profile" {
+				return nil, fmt.Errorf("%s:%d: profile %q: unknown key %q", path, value.Line, name, key)
+			}
+		}
+		profiles[name] = p
+	}
+
+	for _, name := range node.Keys {
+		if _, err := profileChain(profiles, name, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return profiles, nil
+}
+
+// profileChain returns the profile and everything it extends, base first.
+func profileChain(profiles map[string]*profile, name, path string) ([]*profile, error) {
+	var chain []*profile
+	visited := make(map[string]bool)
+
+	for current := name; current != ""; {
+		p, exists := profiles[current]
+		if !exists {
+			if len(chain) == 0 {
+				return nil, fmt.Errorf("unknown profile %q (available: %s)", name, availableProfiles(profiles))
+			}
+			extends := chain[len(chain)-1].node.Fields[extendsKey]
+			return nil, fmt.Errorf("%s:%d: profile %q extends unknown profile %q", path, extends.Line, chain[len(chain)-1].name, current)
+		}
+		if visited[current] {
+			names := make([]string, 0, len(chain)+1)
+			for _, c := range chain {
+				names = append(names, c.name)
+			}
+			names = append(names, current)
+			return nil, fmt.Errorf("%s:%d: profile %q: cyclic inheritance %s", path, profiles[name].node.Line, name, strings.Join(names, " -> "))
+		}
+		visited[current] = true
+		chain = append(chain, p)
+		current = p.extends
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+func availableProfiles(profiles map[string]*profile) string {
+	if len(profiles) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// selectedProfile follows the usual precedence for the profile name itself:
+// flag, then environment, then the "profile" key of the configuration file.
+func selectedProfile(fs *FlagSet, root *configFile.Node, sources map[string]string) string {
+	if sources["profile"] == SourceFlag {
+		return fs.Lookup("profile").Value.String()
+	}
+	if value, ok := os.LookupEnv(EnvName("profile")); ok {
+		return value
+	}
+	if node := root.Get("profile"); node != nil {
+		return node.Value
+	}
+	return ""
+}
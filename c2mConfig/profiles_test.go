@@ -0,0 +1,165 @@
+package c2mConfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const profilesConfig = `languages: [go]
+max-file-size: 1000
+profile: review
+profiles:
+  review:
+    languages: [go, md]
+  backend:
+    extends: review
+    max-file-size: 2000
+  docs:
+    languages: md
+`
+
+func TestProfiles(t *testing.T) {
+	setup := func(t *testing.T, content string) (string, func()) {
+		t.Helper()
+		inputDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(inputDir, ".code2md.yaml"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		return inputDir, setupFlagTest(t)
+	}
+
+	t.Run("profile selected in config file", func(t *testing.T) {
+		inputDir, cleanup := setup(t, profilesConfig)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", inputDir})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if !config.AllowedLanguages[".md"] {
+			t.Errorf("review profile should enable md, got %v", config.AllowedLanguages)
+		}
+		if !strings.HasSuffix(settingSource(config, "languages"), ":6 (profile review)") {
+			t.Errorf("languages source = %q", settingSource(config, "languages"))
+		}
+	})
+
+	t.Run("extended profile inherits and overrides", func(t *testing.T) {
+		inputDir, cleanup := setup(t, profilesConfig)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", inputDir, "--profile", "backend"})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if !config.AllowedLanguages[".md"] {
+			t.Errorf("backend should inherit md from review, got %v", config.AllowedLanguages)
+		}
+		if config.MaxFileSize != 2000 {
+			t.Errorf("MaxFileSize = %d; want 2000", config.MaxFileSize)
+		}
+	})
+
+	t.Run("flags override profile values", func(t *testing.T) {
+		inputDir, cleanup := setup(t, profilesConfig)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", inputDir, "-p", "backend", "-m", "3000"})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if config.MaxFileSize != 3000 {
+			t.Errorf("MaxFileSize = %d; want 3000", config.MaxFileSize)
+		}
+	})
+
+	t.Run("profile from environment", func(t *testing.T) {
+		inputDir, cleanup := setup(t, profilesConfig)
+		defer cleanup()
+		t.Setenv("CODE2MD_PROFILE", "docs")
+
+		config, err := InitializeConfigFromArgs([]string{"-i", inputDir})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if config.AllowedLanguages[".go"] || !config.AllowedLanguages[".md"] {
+			t.Errorf("docs profile should only enable md, got %v", config.AllowedLanguages)
+		}
+	})
+
+	t.Run("profile without config file", func(t *testing.T) {
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		_, err := InitializeConfigFromArgs([]string{"-i", t.TempDir(), "-p", "review"})
+		if err == nil || !strings.Contains(err.Error(), "no config file") {
+			t.Errorf("InitializeConfigFromArgs() error = %v; want no config file error", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			content string
+			args    []string
+			want    string
+		}{
+			{
+				"unknown profile",
+				profilesConfig,
+				[]string{"-p", "missing"},
+				`unknown profile "missing" (available: backend, docs, review)`,
+			},
+			{
+				"unknown key in profile",
+				"profiles:\n  review:\n    budget: 1000\n",
+				nil,
+				`.code2md.yaml:3: profile "review": unknown key "budget"`,
+			},
+			{
+				"nested profile selection",
+				"profiles:\n  review:\n    profile: docs\n",
+				nil,
+				`.code2md.yaml:3: profile "review": unknown key "profile"`,
+			},
+			{
+				"extends unknown profile",
+				"profiles:\n  review:\n    extends: base\n",
+				nil,
+				`.code2md.yaml:3: profile "review" extends unknown profile "base"`,
+			},
+			{
+				"cyclic inheritance",
+				"profiles:\n  a:\n    extends: b\n  b:\n    extends: c\n  c:\n    extends: a\n",
+				nil,
+				`.code2md.yaml:2: profile "a": cyclic inheritance a -> b -> c -> a`,
+			},
+			{
+				"self inheritance",
+				"profiles:\n  a:\n    extends: a\n",
+				nil,
+				`profile "a": cyclic inheritance a -> a`,
+			},
+			{
+				"profile is not a map",
+				"profiles:\n  a: go\n",
+				nil,
+				`.code2md.yaml:2: profile "a" must be a map of settings`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				inputDir, cleanup := setup(t, tt.content)
+				defer cleanup()
+
+				_, err := InitializeConfigFromArgs(append([]string{"-i", inputDir}, tt.args...))
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("InitializeConfigFromArgs() error = %v; want it to contain %q", err, tt.want)
+				}
+			})
+		}
+	})
+}
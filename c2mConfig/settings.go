@@ -0,0 +1,230 @@
+package c2mConfig
+
+import (
+	"code2md/configFile"
+	"code2md/language"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	envPrefix      = "CODE2MD_"
+	languageMapKey = "language-map"
+)
+
+const (
+	SourceDefault = "default"
+	SourceFlag    = "flag"
+)
+
+type Setting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+var unconfigurableSettings = map[string]bool{
+	"help":    true,
+	"version": true,
+	"config":  true,
+}
+
+func EnvName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// resolveSettings layers the configuration file and the environment below the
+// flags already parsed into fs and returns the source of every setting.
+func resolveSettings(fs *FlagSet, configPath string) (map[string]string, error) {
+	sources := make(map[string]string)
+	for _, name := range fs.Names() {
+		sources[name] = SourceDefault
+		if fs.IsSet(name) {
+			sources[name] = SourceFlag
+		}
+	}
+
+	var root *configFile.Node
+	if configPath != "" {
+		var err error
+		root, err = configFile.Load(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading config file: %w", err)
+		}
+		if err := applyConfigFile(fs, root, configPath, sources); err != nil {
+			return nil, err
+		}
+	} else if name := selectedProfile(fs, nil, sources); name != "" {
+		return nil, fmt.Errorf("profile %q selected but no config file found", name)
+	}
+
+	mappings, err := parseLanguageMappings(root.Get(languageMapKey), configPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := language.SetCustomMappings(mappings); err != nil {
+		return nil, fmt.Errorf("%s: %q: %w", configPath, languageMapKey, err)
+	}
+
+	if err := applyEnvironment(fs, sources); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+func applyConfigFile(fs *FlagSet, root *configFile.Node, path string, sources map[string]string) error {
+	profiles, err := parseProfiles(fs, root.Get(profilesKey), path)
+	if err != nil {
+		return err
+	}
+
+	if err := applyConfigValues(fs, root, path, "", sources); err != nil {
+		return err
+	}
+
+	name := selectedProfile(fs, root, sources)
+	if name == "" {
+		return nil
+	}
+	chain, err := profileChain(profiles, name, path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, p := range chain {
+		if err := applyConfigValues(fs, p.node, path, p.name, sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyConfigValues(fs *FlagSet, root *configFile.Node, path, profileName string, sources map[string]string) error {
+	for _, key := range root.Keys {
+		node := root.Fields[key]
+		if (profileName == "" && (key == profilesKey || key == languageMapKey)) || (profileName != "" && key == extendsKey) {
+			continue
+		}
+		if !isConfigurable(fs, key) {
+			return fmt.Errorf("%s:%d: unknown key %q", path, node.Line, key)
+		}
+
+		value, err := node.String()
+		if err != nil {
+			return fmt.Errorf("%s: %q: %w", path, key, err)
+		}
+		if sources[key] == SourceFlag {
+			continue
+		}
+		if err := fs.Set(key, value); err != nil {
+			return fmt.Errorf("%s:%d: invalid value %q for %q: %v", path, node.Line, value, key, err)
+		}
+		sources[key] = fmt.Sprintf("%s:%d", path, node.Line)
+		if profileName != "" {
+			sources[key] += " (profile " + profileName + ")"
+		}
+	}
+	return nil
+}
+
+func applyEnvironment(fs *FlagSet, sources map[string]string) error {
+	for _, name := range fs.Names() {
+		if !isConfigurable(fs, name) || sources[name] == SourceFlag {
+			continue
+		}
+		env := EnvName(name)
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("invalid value %q in %s: %v", value, env, err)
+		}
+		sources[name] = "env " + env
+	}
+	return nil
+}
+
+func isConfigurable(fs *FlagSet, name string) bool {
+	return fs.Lookup(name) != nil && fs.LongName(name) == name && !unconfigurableSettings[name]
+}
+
+func locateConfigFile(fs *FlagSet, values *dumpFlags) (string, error) {
+	explicit := values.configFile
+	if !fs.IsSet("config") {
+		explicit = os.Getenv(EnvName("config"))
+	}
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("config file: %w", err)
+		}
+		return explicit, nil
+	}
+
+	dir := values.inputFolder
+	if !fs.IsSet("input") {
+		dir = os.Getenv(EnvName("input"))
+	}
+	if dir == "" {
+		dir = "."
+	}
+
+	path, err := configFile.Find(dir)
+	if err != nil {
+		return "", fmt.Errorf("looking for config file: %w", err)
+	}
+	return path, nil
+}
+
+func collectSettings(fs *FlagSet, sources map[string]string) []Setting {
+	var settings []Setting
+	for _, name := range fs.Names() {
+		if !isConfigurable(fs, name) {
+			continue
+		}
+		source := sources[name]
+		if source == SourceFlag {
+			source = "flag --" + name
+		}
+		settings = append(settings, Setting{Name: name, Value: fs.Lookup(name).Value.String(), Source: source})
+	}
+	return settings
+}
+
+func parseLanguageMappings(node *configFile.Node, path string) (language.CustomMappings, error) {
+	var mappings language.CustomMappings
+	if node == nil {
+		return mappings, nil
+	}
+	if node.Kind != configFile.MapNode {
+		return mappings, fmt.Errorf("%s:%d: %q must be a map with extensions, filenames and globs", path, node.Line, languageMapKey)
+	}
+
+	for _, kind := range node.Keys {
+		section := node.Fields[kind]
+		var target *[]language.Mapping
+		switch kind {
+		case "extensions":
+			target = &mappings.Extensions
+		case "filenames":
+			target = &mappings.FileNames
+		case "globs":
+			target = &mappings.Globs
+		default:
+			return mappings, fmt.Errorf("%s:%d: %s: unknown key %q", path, section.Line, languageMapKey, kind)
+		}
+		if section.Kind != configFile.MapNode {
+			return mappings, fmt.Errorf("%s:%d: %s.%s must map patterns to fence languages", path, section.Line, languageMapKey, kind)
+		}
+
+		for _, pattern := range section.Keys {
+			fence := section.Fields[pattern]
+			if fence.Kind != configFile.ScalarNode || fence.Value == "" {
+				return mappings, fmt.Errorf("%s:%d: %s.%s: %q must map to a fence language", path, fence.Line, languageMapKey, kind, pattern)
+			}
+			*target = append(*target, language.Mapping{Pattern: pattern, Fence: fence.Value})
+		}
+	}
+	return mappings, nil
+}
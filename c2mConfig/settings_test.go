@@ -0,0 +1,193 @@
+package c2mConfig
+
+import (
+	"code2md/language"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func settingSource(config *Config, name string) string {
+	for _, s := range config.Settings {
+		if s.Name == name {
+			return s.Source
+		}
+	}
+	return ""
+}
+
+func TestConfigFilePrecedence(t *testing.T) {
+	writeConfig := func(t *testing.T, dir, name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		return path
+	}
+
+	t.Run("config file discovered in input folder overrides defaults", func(t *testing.T) {
+		inputDir := t.TempDir()
+		writeConfig(t, inputDir, ".code2md.yaml", "languages: [go]\nmax-file-size: 2048\nignore:\n  - \"*.log\"\n")
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", inputDir})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+
+		if config.MaxFileSize != 2048 {
+			t.Errorf("MaxFileSize = %d; want 2048", config.MaxFileSize)
+		}
+		if !config.AllowedLanguages[".go"] || config.AllowedLanguages[".js"] {
+			t.Errorf("AllowedLanguages should only enable go, got %v", config.AllowedLanguages)
+		}
+		if !sliceContains(config.IgnorePatterns, "*.log") || sliceContains(config.IgnorePatterns, "*.xml") {
+			t.Errorf("ignore from config file should replace defaults, got %v", config.IgnorePatterns)
+		}
+		if want := filepath.Join(inputDir, ".code2md.yaml") + ":2"; settingSource(config, "max-file-size") != want {
+			t.Errorf("max-file-size source = %q; want %q", settingSource(config, "max-file-size"), want)
+		}
+		if settingSource(config, "output") != SourceDefault {
+			t.Errorf("output source = %q; want default", settingSource(config, "output"))
+		}
+	})
+
+	t.Run("environment overrides config file and flags override environment", func(t *testing.T) {
+		inputDir := t.TempDir()
+		writeConfig(t, inputDir, ".code2md.toml", "max-file-size = 2048\noutput = \"from-file.md\"\n")
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+		t.Setenv("CODE2MD_MAX_FILE_SIZE", "4096")
+		t.Setenv("CODE2MD_OUTPUT", "from-env.md")
+
+		config, err := InitializeConfigFromArgs([]string{"-i", inputDir, "-o", "from-flag.md"})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+
+		if config.MaxFileSize != 4096 {
+			t.Errorf("MaxFileSize = %d; want 4096 from environment", config.MaxFileSize)
+		}
+		if config.OutputMarkdown != "from-flag.md" {
+			t.Errorf("OutputMarkdown = %q; want from-flag.md", config.OutputMarkdown)
+		}
+		if settingSource(config, "max-file-size") != "env CODE2MD_MAX_FILE_SIZE" {
+			t.Errorf("max-file-size source = %q", settingSource(config, "max-file-size"))
+		}
+		if settingSource(config, "output") != "flag --output" {
+			t.Errorf("output source = %q", settingSource(config, "output"))
+		}
+	})
+
+	t.Run("explicit config path", func(t *testing.T) {
+		configDir := t.TempDir()
+		path := writeConfig(t, configDir, "custom.yaml", "max-file-size: 1000\n")
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", t.TempDir(), "--config", path})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if config.ConfigFile != path || config.MaxFileSize != 1000 {
+			t.Errorf("ConfigFile = %q, MaxFileSize = %d; want %q, 1000", config.ConfigFile, config.MaxFileSize, path)
+		}
+	})
+
+	t.Run("missing explicit config file errors", func(t *testing.T) {
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		if _, err := InitializeConfigFromArgs([]string{"-i", ".", "--config", "missing.yaml"}); err == nil {
+			t.Error("InitializeConfigFromArgs() should error for missing config file")
+		}
+	})
+
+	t.Run("errors name file, line and key", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			content string
+			want    string
+		}{
+			{"unknown key", "input: .\nunknown: x\n", ".code2md.yaml:2: unknown key \"unknown\""},
+			{"shorthand key", "l: go\n", ".code2md.yaml:1: unknown key \"l\""},
+			{"unconfigurable key", "help: true\n", ".code2md.yaml:1: unknown key \"help\""},
+			{"invalid value", "max-file-size: lots\n", ".code2md.yaml:1: invalid value \"lots\" for \"max-file-size\""},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				inputDir := t.TempDir()
+				writeConfig(t, inputDir, ".code2md.yaml", tt.content)
+				cleanup := setupFlagTest(t)
+				defer cleanup()
+
+				_, err := InitializeConfigFromArgs([]string{"-i", inputDir})
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("InitializeConfigFromArgs() error = %v; want it to contain %q", err, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("max-file-size"); got != "CODE2MD_MAX_FILE_SIZE" {
+		t.Errorf("EnvName() = %q; want CODE2MD_MAX_FILE_SIZE", got)
+	}
+}
+
+func TestLanguageMappings(t *testing.T) {
+	defer language.SetCustomMappings(language.CustomMappings{})
+
+	t.Run("registers mappings from config file", func(t *testing.T) {
+		inputDir := t.TempDir()
+		content := "language-map:\n  extensions:\n    .foo: foolang\n  filenames:\n    Jenkinsfile: groovy\n  globs:\n    \"*.tmpl\": gotemplate\n"
+		os.WriteFile(filepath.Join(inputDir, ".code2md.yaml"), []byte(content), 0644)
+		cleanup := setupFlagTest(t)
+		defer cleanup()
+
+		config, err := InitializeConfigFromArgs([]string{"-i", inputDir})
+		if err != nil {
+			t.Fatalf("InitializeConfigFromArgs() error: %v", err)
+		}
+		if !config.AllowedLanguages[".foo"] {
+			t.Errorf("custom extension should be allowed, got %v", config.AllowedLanguages)
+		}
+		if got := language.GetMarkdownLanguage("Jenkinsfile", config.AllowedFileNames); got != "groovy" {
+			t.Errorf("GetMarkdownLanguage(Jenkinsfile) = %q; want groovy", got)
+		}
+		if got := language.GetMarkdownLanguage("page.tmpl", config.AllowedFileNames); got != "gotemplate" {
+			t.Errorf("GetMarkdownLanguage(page.tmpl) = %q; want gotemplate", got)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			content string
+			want    string
+		}{
+			{"unknown section", "language-map:\n  languages:\n    .foo: x\n", `.code2md.yaml:2: language-map: unknown key "languages"`},
+			{"list instead of map", "language-map:\n  extensions: [.foo]\n", `.code2md.yaml:2: language-map.extensions must map patterns`},
+			{"invalid extension", "language-map:\n  extensions:\n    foo: x\n", `invalid extension "foo"`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				inputDir := t.TempDir()
+				os.WriteFile(filepath.Join(inputDir, ".code2md.yaml"), []byte(tt.content), 0644)
+				cleanup := setupFlagTest(t)
+				defer cleanup()
+
+				_, err := InitializeConfigFromArgs([]string{"-i", inputDir})
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("InitializeConfigFromArgs() error = %v; want it to contain %q", err, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,375 @@
+package commentStripper
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var licenseKeywords = regexp.MustCompile(`(?i)copyright|licen[cs]e|spdx-license-identifier|all rights reserved`)
+
+// IsSupported reports whether comments of the fence language can be stripped.
+func IsSupported(fence string) bool {
+	_, ok := fenceSyntaxes[fence]
+	return ok
+}
+
+// Strip removes comments, and docstrings in Python, from content written in
+// the given fence language. Lines that only held a comment are dropped.
+// Content of unsupported languages is returned unchanged.
+func Strip(content []byte, fence string) []byte {
+	s, ok := fenceSyntaxes[fence]
+	if !ok {
+		return content
+	}
+	l := &lexer{syntax: s, src: content}
+	l.run()
+	return l.out
+}
+
+// StripLicense removes a leading comment block that mentions a copyright or
+// license, keeping a shebang line.
+func StripLicense(content []byte, fence string) []byte {
+	s, ok := fenceSyntaxes[fence]
+	if !ok {
+		return content
+	}
+
+	start := 0
+	if bytes.HasPrefix(content, []byte("#!")) {
+		start = lineEnd(content, 0)
+	}
+	begin := skipBlankLines(content, start)
+	end := s.leadingComment(content, begin)
+	if end == begin || !licenseKeywords.Match(content[begin:end]) {
+		return content
+	}
+
+	result := append([]byte{}, content[:start]...)
+	return append(result, content[skipBlankLines(content, end):]...)
+}
+
+// leadingComment returns the end of the comment starting at i: a block
+// comment or consecutive lines of line comments, including the final newline.
+func (s *syntax) leadingComment(src []byte, i int) int {
+	rest := src[i:]
+	for _, block := range s.blockComments {
+		if bytes.HasPrefix(rest, []byte(block[0])) {
+			end := bytes.Index(rest[len(block[0]):], []byte(block[1]))
+			if end < 0 {
+				return len(src)
+			}
+			return lineEnd(src, i+len(block[0])+end+len(block[1]))
+		}
+	}
+
+	end := i
+	for end < len(src) {
+		line := strings.TrimLeft(string(src[end:lineEnd(src, end)]), " \t")
+		if s.lineCommentAt(line) == "" || s.isKept(line) {
+			break
+		}
+		end = lineEnd(src, end)
+	}
+	return end
+}
+
+func (s *syntax) lineCommentAt(text string) string {
+	for _, prefix := range s.lineComments {
+		if strings.HasPrefix(text, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+func (s *syntax) isKept(text string) bool {
+	for _, prefix := range s.keep {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lineEnd returns the index after the newline ending the line at i.
+func lineEnd(src []byte, i int) int {
+	if n := bytes.IndexByte(src[i:], '\n'); n >= 0 {
+		return i + n + 1
+	}
+	return len(src)
+}
+
+func skipBlankLines(src []byte, i int) int {
+	for i < len(src) {
+		end := lineEnd(src, i)
+		if len(bytes.TrimSpace(src[i:end])) > 0 {
+			break
+		}
+		i = end
+	}
+	return i
+}
+
+type lexer struct {
+	*syntax
+	src []byte
+	pos int
+	out []byte
+	// lineStart is the offset of the current line in out, removed records
+	// whether a comment was removed from it.
+	lineStart int
+	removed   bool
+	// dropBlank is set after dropping a comment line, so that the blank
+	// lines around it are not doubled.
+	dropBlank bool
+}
+
+func (l *lexer) run() {
+	if bytes.HasPrefix(l.src, []byte("#!")) {
+		l.copyTo(lineEnd(l.src, 0))
+	}
+
+	for l.pos < len(l.src) {
+		rest := l.src[l.pos:]
+		if end := l.blockComment(rest); end > 0 {
+			l.remove(end)
+			continue
+		}
+		if prefix := l.lineCommentAt(string(rest[:minInt(len(rest), 4)])); prefix != "" && l.isLineComment(prefix) {
+			end := bytes.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			if l.isKept(string(rest[:minInt(end, 16)])) {
+				l.copyTo(l.pos + end)
+				continue
+			}
+			l.remove(end)
+			continue
+		}
+		if end := l.rawString(rest); end > 0 {
+			if l.docstrings && l.isDocstring(end) {
+				l.remove(end)
+				continue
+			}
+			l.copyTo(l.pos + end)
+			continue
+		}
+		if l.heredocs && bytes.HasPrefix(rest, []byte("<<")) {
+			if end := heredoc(rest); end > 0 {
+				l.copyTo(l.pos + end)
+				continue
+			}
+		}
+		if end := l.quoted(rest); end > 0 {
+			l.copyTo(l.pos + end)
+			continue
+		}
+		l.copyTo(l.pos + 1)
+	}
+	l.endLine(false)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (l *lexer) blockComment(rest []byte) int {
+	for _, block := range l.blockComments {
+		if !bytes.HasPrefix(rest, []byte(block[0])) {
+			continue
+		}
+		end := bytes.Index(rest[len(block[0]):], []byte(block[1]))
+		if end < 0 {
+			return len(rest)
+		}
+		return len(block[0]) + end + len(block[1])
+	}
+	return 0
+}
+
+func (l *lexer) isLineComment(prefix string) bool {
+	if prefix != "#" || !l.hashAfterSpace || l.pos == 0 {
+		return true
+	}
+	prev := l.src[l.pos-1]
+	return prev == ' ' || prev == '\t' || prev == '\n' || prev == ';'
+}
+
+func (l *lexer) rawString(rest []byte) int {
+	for _, raw := range l.rawStrings {
+		if !bytes.HasPrefix(rest, []byte(raw[0])) || (isIdentifierByte(raw[0][0]) && l.pos > 0 && isIdentifierByte(l.src[l.pos-1])) {
+			continue
+		}
+		end := bytes.Index(rest[len(raw[0]):], []byte(raw[1]))
+		if end < 0 {
+			return len(rest)
+		}
+		return len(raw[0]) + end + len(raw[1])
+	}
+	if l.cppRawStrings && bytes.HasPrefix(rest, []byte(`R"`)) {
+		open := bytes.IndexByte(rest, '(')
+		if open < 0 || open > 18 {
+			return 0
+		}
+		closing := []byte(")" + string(rest[2:open]) + `"`)
+		end := bytes.Index(rest[open:], closing)
+		if end < 0 {
+			return len(rest)
+		}
+		return open + end + len(closing)
+	}
+	return 0
+}
+
+func (l *lexer) quoted(rest []byte) int {
+	quote := rest[0]
+	if quote == '\'' && l.charLiterals {
+		return charLiteral(rest)
+	}
+	if strings.IndexByte(l.quotes, quote) < 0 {
+		return 0
+	}
+	multiline := strings.IndexByte(l.multilineQuotes, quote) >= 0
+	for i := 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			if !multiline {
+				return i
+			}
+		}
+	}
+	return len(rest)
+}
+
+// charLiteral matches 'x', '\n' or 'é', and nothing for lifetimes such
+// as 'a or identifiers ending in a prime.
+func charLiteral(rest []byte) int {
+	if len(rest) > 1 && rest[1] == '\\' {
+		for i := 3; i < minInt(len(rest), 12); i++ {
+			if rest[i] == '\'' {
+				return i + 1
+			}
+			if rest[i] == '\n' {
+				return 0
+			}
+		}
+		return 0
+	}
+	_, size := utf8.DecodeRune(rest[1:])
+	if size > 0 && len(rest) > 1+size && rest[1+size] == '\'' {
+		return size + 2
+	}
+	return 0
+}
+
+// heredoc matches a shell here-document from << to its terminator line.
+func heredoc(rest []byte) int {
+	header := rest[2:lineEnd(rest, 0)]
+	header = bytes.TrimPrefix(header, []byte("-"))
+	header = bytes.TrimLeft(header, " \t")
+	if len(header) == 0 || header[0] == '<' {
+		return 0
+	}
+	word := header
+	if quote := word[0]; quote == '\'' || quote == '"' {
+		end := bytes.IndexByte(word[1:], quote)
+		if end < 0 {
+			return 0
+		}
+		word = word[1 : end+1]
+	} else {
+		if !isIdentifierByte(word[0]) || (word[0] >= '0' && word[0] <= '9') {
+			return 0
+		}
+		n := 0
+		for n < len(word) && (isIdentifierByte(word[n]) || word[n] == '-') {
+			n++
+		}
+		word = word[:n]
+	}
+	if len(word) == 0 {
+		return 0
+	}
+
+	for i := lineEnd(rest, 0); i < len(rest); {
+		end := lineEnd(rest, i)
+		if string(bytes.TrimSpace(rest[i:end])) == string(word) {
+			return i + len(bytes.TrimRight(rest[i:end], "\r\n"))
+		}
+		i = end
+	}
+	return len(rest)
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// isDocstring reports whether the string literal ending at pos+end forms a
+// statement of its own, which is how Python docstrings are written.
+func (l *lexer) isDocstring(end int) bool {
+	if len(bytes.TrimSpace(l.out[l.lineStart:])) > 0 {
+		return false
+	}
+	after := l.src[l.pos+end : lineEnd(l.src, l.pos+end)]
+	if trimmed := bytes.TrimSpace(after); len(trimmed) > 0 && trimmed[0] != '#' {
+		return false
+	}
+	prev := bytes.TrimRight(l.out, " \t\n")
+	if len(prev) == 0 {
+		return true
+	}
+	return !strings.ContainsRune("([{,=+\\%", rune(prev[len(prev)-1]))
+}
+
+func (l *lexer) remove(n int) {
+	l.pos += n
+	l.removed = true
+}
+
+func (l *lexer) copyTo(end int) {
+	for ; l.pos < end; l.pos++ {
+		b := l.src[l.pos]
+		if b == '\n' {
+			l.endLine(true)
+			continue
+		}
+		l.out = append(l.out, b)
+	}
+}
+
+// endLine finishes the current output line, dropping it when removing
+// comments left it blank.
+func (l *lexer) endLine(newline bool) {
+	line := l.out[l.lineStart:]
+	blank := len(bytes.TrimSpace(line)) == 0
+	switch {
+	case l.removed && blank:
+		l.out = l.out[:l.lineStart]
+		l.dropBlank = true
+	case blank && l.dropBlank && (l.lineStart == 0 || bytes.HasSuffix(l.out[:l.lineStart], []byte("\n\n"))):
+		l.out = l.out[:l.lineStart]
+	default:
+		if l.removed {
+			l.out = append(l.out[:l.lineStart], bytes.TrimRight(line, " \t")...)
+		}
+		if newline {
+			l.out = append(l.out, '\n')
+		}
+		if !blank {
+			l.dropBlank = false
+		}
+	}
+	l.lineStart = len(l.out)
+	l.removed = false
+}
@@ -0,0 +1,193 @@
+package commentStripper
+
+import (
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		fence string
+		input string
+		want  string
+	}{
+		{
+			"go line and block comments",
+			"go",
+			"// Package main does things.\npackage main\n\n/*\nBlock comment.\n*/\nfunc main() { // trailing\n\tx := 1 /* inline */ + 2\n}\n",
+			"package main\n\nfunc main() {\n\tx := 1  + 2\n}\n",
+		},
+		{
+			"go strings and raw strings are kept",
+			"go",
+			"var a = \"// not a comment\"\nvar b = `/* raw\n// string */`\nvar c = '\"' // quote\n",
+			"var a = \"// not a comment\"\nvar b = `/* raw\n// string */`\nvar c = '\"'\n",
+		},
+		{
+			"go directives are kept",
+			"go",
+			"//go:build linux\n\n// Package x.\npackage x\n\n//go:generate stringer -type=Kind\n",
+			"//go:build linux\n\npackage x\n\n//go:generate stringer -type=Kind\n",
+		},
+		{
+			"escaped quotes",
+			"js",
+			"const s = \"a \\\" // b\"; // c\nconst t = 'it\\'s /* x */';\n",
+			"const s = \"a \\\" // b\";\nconst t = 'it\\'s /* x */';\n",
+		},
+		{
+			"template literals",
+			"ts",
+			"const q = `\n// kept\n`;\n// removed\n",
+			"const q = `\n// kept\n`;\n",
+		},
+		{
+			"unterminated string ends at the line",
+			"js",
+			"const r = /\"/; // removed\nconst x = 1; // removed too\n",
+			"const r = /\"/; // removed\nconst x = 1;\n",
+		},
+		{
+			"rust raw strings and lifetimes",
+			"rust",
+			"fn f<'a>(s: &'a str) -> &'a str { s } // c\nlet r = r#\"// \"quoted\" \"#;\nlet c = '\"'; // d\n",
+			"fn f<'a>(s: &'a str) -> &'a str { s }\nlet r = r#\"// \"quoted\" \"#;\nlet c = '\"';\n",
+		},
+		{
+			"cpp raw strings",
+			"cpp",
+			"auto s = R\"x(// )\" /* )x\"; // c\n",
+			"auto s = R\"x(// )\" /* )x\";\n",
+		},
+		{
+			"shell comments and special hashes",
+			"sh",
+			"#!/bin/bash\n# comment\necho \"# kept\" '# kept' $# ${#arr[@]} # removed\n",
+			"#!/bin/bash\necho \"# kept\" '# kept' $# ${#arr[@]}\n",
+		},
+		{
+			"shell heredocs",
+			"sh",
+			"cat <<EOF\n# kept\nEOF\n# removed\ncat <<-'END'\n\t# kept\n\tEND\n",
+			"cat <<EOF\n# kept\nEOF\ncat <<-'END'\n\t# kept\n\tEND\n",
+		},
+		{
+			"python docstrings",
+			"py",
+			"\"\"\"Module docstring.\"\"\"\n\nimport os\n\n\ndef f(x):\n    \"\"\"Docstring\n    over lines.\"\"\"\n    s = \"\"\"kept\"\"\"\n    t = (\n        \"\"\"kept too\"\"\"\n    )\n    return x  # comment\n",
+			"import os\n\n\ndef f(x):\n    s = \"\"\"kept\"\"\"\n    t = (\n        \"\"\"kept too\"\"\"\n    )\n    return x\n",
+		},
+		{
+			"python hash in strings",
+			"py",
+			"s = '#not' + \"#not\"  # yes\n",
+			"s = '#not' + \"#not\"\n",
+		},
+		{
+			"html comments",
+			"html",
+			"<!-- header -->\n<p>text</p> <!-- note -->\n<![CDATA[ <!-- kept --> ]]>\n",
+			"<p>text</p>\n<![CDATA[ <!-- kept --> ]]>\n",
+		},
+		{
+			"sql comments",
+			"sql",
+			"-- migration\nSELECT '--kept', \"a--b\" FROM t; /* block */\n",
+			"SELECT '--kept', \"a--b\" FROM t;\n",
+		},
+		{
+			"yaml comments",
+			"yaml",
+			"# config\nurl: http://example.com/#anchor # comment\nkey: 'a # b'\n",
+			"url: http://example.com/#anchor\nkey: 'a # b'\n",
+		},
+		{
+			"blank lines are not doubled",
+			"go",
+			"package x\n\n// A is a.\n\nvar a = 1\n\n\n// B\nvar b = 2",
+			"package x\n\nvar a = 1\n\n\nvar b = 2",
+		},
+		{
+			"unsupported language unchanged",
+			"md",
+			"# Title\n<!-- c -->\n",
+			"# Title\n<!-- c -->\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Strip([]byte(tt.input), tt.fence)); got != tt.want {
+				t.Errorf("Strip() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripLicense(t *testing.T) {
+	tests := []struct {
+		name  string
+		fence string
+		input string
+		want  string
+	}{
+		{
+			"line comment banner",
+			"go",
+			"// Copyright 2024 Example Inc.\n// Use of this source code is governed by the MIT license.\n\n// Package x does things.\npackage x\n",
+			"// Package x does things.\npackage x\n",
+		},
+		{
+			"block comment banner",
+			"java",
+			"/*\n * Licensed under the Apache License, Version 2.0\n */\npackage com.example;\n",
+			"package com.example;\n",
+		},
+		{
+			"shebang is kept",
+			"py",
+			"#!/usr/bin/env python3\n# SPDX-License-Identifier: MIT\n\nimport os\n",
+			"#!/usr/bin/env python3\nimport os\n",
+		},
+		{
+			"doc comment without license is kept",
+			"go",
+			"// Package x does things.\npackage x\n",
+			"// Package x does things.\npackage x\n",
+		},
+		{
+			"only the leading block is considered",
+			"go",
+			"package x\n\n// Copyright notice in the middle.\nvar a = 1\n",
+			"package x\n\n// Copyright notice in the middle.\nvar a = 1\n",
+		},
+		{
+			"build constraints end the banner",
+			"go",
+			"//go:build linux\n\n// Copyright 2024\npackage x\n",
+			"//go:build linux\n\n// Copyright 2024\npackage x\n",
+		},
+		{
+			"html banner",
+			"html",
+			"<!-- Copyright 2024 Example -->\n<html></html>\n",
+			"<html></html>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(StripLicense([]byte(tt.input), tt.fence)); got != tt.want {
+				t.Errorf("StripLicense() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSupported(t *testing.T) {
+	for fence, want := range map[string]bool{"go": true, "py": true, "sql": true, "html": true, "md": false, "json": false} {
+		if got := IsSupported(fence); got != want {
+			t.Errorf("IsSupported(%q) = %v; want %v", fence, got, want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package commentStripper
+
+// syntax describes the comments and string literals of a language family.
+// String literals are copied unchanged so that comment markers inside them
+// are never mistaken for comments.
+type syntax struct {
+	lineComments  []string
+	blockComments [][2]string
+	// quotes delimit strings with backslash escapes; multilineQuotes lists
+	// those that may span lines, the others end at the end of the line.
+	quotes          string
+	multilineQuotes string
+	// rawStrings are copied verbatim up to their closing delimiter.
+	rawStrings [][2]string
+	// charLiterals treats ' as a character literal delimiter only when it
+	// encloses a single (escaped) character, e.g. to survive Rust lifetimes.
+	charLiterals  bool
+	cppRawStrings bool
+	// hashAfterSpace only accepts # at the start of a word, as in shell
+	// scripts where $# and ${#var} are no comments.
+	hashAfterSpace bool
+	heredocs       bool
+	docstrings     bool
+	// keep lists line comment prefixes that carry meaning, e.g. //go:build.
+	keep []string
+}
+
+var (
+	cStyle = &syntax{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"`,
+		charLiterals:  true,
+	}
+	goSyntax = &syntax{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"`,
+		rawStrings:    [][2]string{{"`", "`"}},
+		charLiterals:  true,
+		keep:          []string{"//go:", "//line ", "// +build", "//export ", "//nolint"},
+	}
+	cppSyntax = &syntax{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"`,
+		charLiterals:  true,
+		cppRawStrings: true,
+	}
+	csharpSyntax = &syntax{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"`,
+		rawStrings:    [][2]string{{`"""`, `"""`}, {`@"`, `"`}},
+		charLiterals:  true,
+	}
+	jvmSyntax = &syntax{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"`,
+		rawStrings:    [][2]string{{`"""`, `"""`}},
+		charLiterals:  true,
+	}
+	rustSyntax = &syntax{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"`,
+		rawStrings:    [][2]string{{`r###"`, `"###`}, {`r##"`, `"##`}, {`r#"`, `"#`}, {`r"`, `"`}, {`br"`, `"`}},
+		charLiterals:  true,
+	}
+	jsSyntax = &syntax{
+		lineComments:    []string{"//"},
+		blockComments:   [][2]string{{"/*", "*/"}},
+		quotes:          "\"'`",
+		multilineQuotes: "`",
+	}
+	phpSyntax = &syntax{
+		lineComments:    []string{"//", "#"},
+		blockComments:   [][2]string{{"/*", "*/"}},
+		quotes:          `"'`,
+		multilineQuotes: `"'`,
+		keep:            []string{"#["},
+	}
+	cssSyntax = &syntax{
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"'`,
+	}
+	scssSyntax = &syntax{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"'`,
+	}
+	hashSyntax = &syntax{
+		lineComments:   []string{"#"},
+		quotes:         `"'`,
+		hashAfterSpace: true,
+	}
+	shellSyntax = &syntax{
+		lineComments:    []string{"#"},
+		quotes:          `"`,
+		multilineQuotes: `"`,
+		rawStrings:      [][2]string{{"'", "'"}},
+		hashAfterSpace:  true,
+		heredocs:        true,
+	}
+	rubySyntax = &syntax{
+		lineComments:    []string{"#"},
+		quotes:          `"'`,
+		multilineQuotes: `"'`,
+	}
+	pythonSyntax = &syntax{
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+		rawStrings:   [][2]string{{`"""`, `"""`}, {"'''", "'''"}},
+		docstrings:   true,
+	}
+	hclSyntax = &syntax{
+		lineComments:  []string{"#", "//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"`,
+	}
+	markupSyntax = &syntax{
+		blockComments: [][2]string{{"<!--", "-->"}},
+		rawStrings:    [][2]string{{"<![CDATA[", "]]>"}},
+	}
+	sqlSyntax = &syntax{
+		lineComments:    []string{"--"},
+		blockComments:   [][2]string{{"/*", "*/"}},
+		quotes:          `"'`,
+		multilineQuotes: `'`,
+	}
+	luaSyntax = &syntax{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"--[[", "]]"}},
+		quotes:        `"'`,
+		rawStrings:    [][2]string{{"[[", "]]"}},
+	}
+	haskellSyntax = &syntax{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"{-", "-}"}},
+		quotes:        `"`,
+		charLiterals:  true,
+	}
+)
+
+var fenceSyntaxes = map[string]*syntax{
+	"c":            cStyle,
+	"cuda":         cppSyntax,
+	"cpp":          cppSyntax,
+	"csharp":       csharpSyntax,
+	"d":            cStyle,
+	"dart":         jsSyntax,
+	"glsl":         cStyle,
+	"go":           goSyntax,
+	"groovy":       jvmSyntax,
+	"haxe":         jsSyntax,
+	"hlsl":         cStyle,
+	"java":         jvmSyntax,
+	"apex":         jvmSyntax,
+	"kotlin":       jvmSyntax,
+	"objectivec":   cStyle,
+	"protobuf":     cStyle,
+	"prisma":       cStyle,
+	"rust":         rustSyntax,
+	"scala":        jvmSyntax,
+	"solidity":     cStyle,
+	"swift":        jvmSyntax,
+	"thrift":       cStyle,
+	"vala":         cStyle,
+	"wgsl":         cStyle,
+	"zig":          cStyle,
+	"js":           jsSyntax,
+	"jsx":          jsSyntax,
+	"ts":           jsSyntax,
+	"tsx":          jsSyntax,
+	"php":          phpSyntax,
+	"css":          cssSyntax,
+	"less":         scssSyntax,
+	"scss":         scssSyntax,
+	"sh":           shellSyntax,
+	"fish":         hashSyntax,
+	"nu":           hashSyntax,
+	"dockerfile":   hashSyntax,
+	"makefile":     hashSyntax,
+	"cmake":        hashSyntax,
+	"just":         hashSyntax,
+	"awk":          hashSyntax,
+	"perl":         hashSyntax,
+	"r":            hashSyntax,
+	"tcl":          hashSyntax,
+	"toml":         hashSyntax,
+	"yaml":         hashSyntax,
+	"graphql":      hashSyntax,
+	"nim":          hashSyntax,
+	"elixir":       hashSyntax,
+	"crystal":      rubySyntax,
+	"ruby":         rubySyntax,
+	"coffeescript": rubySyntax,
+	"powershell":   hashSyntax,
+	"starlark":     pythonSyntax,
+	"py":           pythonSyntax,
+	"mojo":         pythonSyntax,
+	"hcl":          hclSyntax,
+	"html":         markupSyntax,
+	"xml":          markupSyntax,
+	"svelte":       markupSyntax,
+	"vue":          markupSyntax,
+	"sql":          sqlSyntax,
+	"lua":          luaSyntax,
+	"haskell":      haskellSyntax,
+	"elm":          haskellSyntax,
+	"purescript":   haskellSyntax,
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"code2md/c2mConfig"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "show" {
+		return errors.New("usage: code2md config show [dump flags]")
+	}
+
+	config, err := c2mConfig.InitializeConfigFromArgs(args[1:])
+	if err != nil {
+		return fmt.Errorf("initializing config: %w", err)
+	}
+
+	displayConfig(config)
+	return nil
+}
+
+func displayConfig(config *c2mConfig.Config) {
+	configFile := config.ConfigFile
+	if configFile == "" {
+		configFile = "none"
+	}
+	fmt.Printf("Configuration file: %s\n\n", configFile)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+	for _, s := range config.Settings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.Value, s.Source)
+	}
+	w.Flush()
+}
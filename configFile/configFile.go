@@ -0,0 +1,207 @@
+package configFile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Kind int
+
+const (
+	ScalarNode Kind = iota
+	ListNode
+	MapNode
+)
+
+type Node struct {
+	Kind   Kind
+	Line   int
+	Value  string
+	Items  []*Node
+	Keys   []string
+	Fields map[string]*Node
+}
+
+var FileNames = []string{".code2md.yaml", ".code2md.yml", ".code2md.toml"}
+
+func newMap(line int) *Node {
+	return &Node{Kind: MapNode, Line: line, Fields: make(map[string]*Node)}
+}
+
+func (n *Node) set(key string, value *Node) error {
+	if _, exists := n.Fields[key]; exists {
+		return fmt.Errorf("line %d: duplicate key %q", value.Line, key)
+	}
+	n.Keys = append(n.Keys, key)
+	n.Fields[key] = value
+	return nil
+}
+
+func (n *Node) Get(key string) *Node {
+	if n == nil || n.Kind != MapNode {
+		return nil
+	}
+	return n.Fields[key]
+}
+
+// String flattens scalars and lists of scalars into the comma-separated form
+// used by the command-line flags.
+func (n *Node) String() (string, error) {
+	switch n.Kind {
+	case ScalarNode:
+		return n.Value, nil
+	case ListNode:
+		values := make([]string, 0, len(n.Items))
+		for _, item := range n.Items {
+			if item.Kind != ScalarNode {
+				return "", fmt.Errorf("line %d: nested lists and maps are not supported here", item.Line)
+			}
+			values = append(values, item.Value)
+		}
+		return strings.Join(values, ","), nil
+	default:
+		return "", fmt.Errorf("line %d: expected a value or a list, got a map", n.Line)
+	}
+}
+
+func Load(path string) (*Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *Node
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		root, err = ParseTOML(data)
+	} else {
+		root, err = ParseYAML(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return root, nil
+}
+
+// Find returns the first configuration file from FileNames present in dir.
+func Find(dir string) (string, error) {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+func unquote(s string, line int) (string, error) {
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("line %d: unterminated string %s", line, s)
+	}
+	body := s[1 : len(s)-1]
+
+	if s[0] == '\'' {
+		return strings.ReplaceAll(body, "''", "'"), nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] != '\\' {
+			sb.WriteByte(body[i])
+			continue
+		}
+		i++
+		if i >= len(body) {
+			return "", fmt.Errorf("line %d: invalid escape at end of string", line)
+		}
+		switch body[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case '"', '\\', '/':
+			sb.WriteByte(body[i])
+		default:
+			return "", fmt.Errorf("line %d: unsupported escape \\%c", line, body[i])
+		}
+	}
+	return sb.String(), nil
+}
+
+// findUnquoted returns the index of the first occurrence of sep outside of
+// single or double quoted strings, or -1.
+func findUnquoted(s string, sep func(s string, i int) bool) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case sep(s, i):
+			return i
+		}
+	}
+	return -1
+}
+
+func stripComment(s string) string {
+	i := findUnquoted(s, func(s string, i int) bool {
+		return s[i] == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t')
+	})
+	if i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, " \t")
+}
+
+func splitList(s string, line int) ([]*Node, error) {
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if inner == "" {
+		return nil, nil
+	}
+
+	var items []*Node
+	for inner != "" {
+		i := findUnquoted(inner, func(s string, i int) bool { return s[i] == ',' })
+		part := inner
+		if i >= 0 {
+			part, inner = inner[:i], strings.TrimSpace(inner[i+1:])
+		} else {
+			inner = ""
+		}
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if i >= 0 && inner == "" {
+				break
+			}
+			return nil, fmt.Errorf("line %d: empty list element", line)
+		}
+		value, err := parseScalar(part, line)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &Node{Kind: ScalarNode, Line: line, Value: value})
+	}
+	return items, nil
+}
+
+func parseScalar(s string, line int) (string, error) {
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		return unquote(s, line)
+	}
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		return "", fmt.Errorf("line %d: nested lists and inline maps are not supported", line)
+	}
+	return s, nil
+}
@@ -0,0 +1,75 @@
+package configFile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func flatten(t *testing.T, n *Node) map[string]string {
+	t.Helper()
+	result := make(map[string]string)
+	var walk func(prefix string, n *Node)
+	walk = func(prefix string, n *Node) {
+		if n.Kind == MapNode {
+			for _, key := range n.Keys {
+				name := key
+				if prefix != "" {
+					name = prefix + "." + key
+				}
+				walk(name, n.Fields[key])
+			}
+			return
+		}
+		value, err := n.String()
+		if err != nil {
+			t.Fatalf("String() error: %v", err)
+		}
+		result[prefix] = value
+	}
+	walk("", n)
+	return result
+}
+
+func TestLoadAndFind(t *testing.T) {
+	t.Run("finds yaml before toml", func(t *testing.T) {
+		tempDir := t.TempDir()
+		os.WriteFile(filepath.Join(tempDir, ".code2md.toml"), []byte("input = \"toml\"\n"), 0644)
+		os.WriteFile(filepath.Join(tempDir, ".code2md.yaml"), []byte("input: yaml\n"), 0644)
+
+		path, err := Find(tempDir)
+		if err != nil {
+			t.Fatalf("Find() error: %v", err)
+		}
+		if filepath.Base(path) != ".code2md.yaml" {
+			t.Errorf("Find() = %q; want .code2md.yaml", path)
+		}
+	})
+
+	t.Run("returns empty path when nothing found", func(t *testing.T) {
+		path, err := Find(t.TempDir())
+		if err != nil || path != "" {
+			t.Errorf("Find() = %q, %v; want empty path", path, err)
+		}
+	})
+
+	t.Run("loads by extension and prefixes errors with path", func(t *testing.T) {
+		tempDir := t.TempDir()
+		path := filepath.Join(tempDir, ".code2md.toml")
+		os.WriteFile(path, []byte("input = \"src\"\n"), 0644)
+
+		root, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if root.Get("input").Value != "src" {
+			t.Errorf("input = %q; want src", root.Get("input").Value)
+		}
+
+		os.WriteFile(path, []byte("input\n"), 0644)
+		if _, err := Load(path); err == nil || !strings.HasPrefix(err.Error(), path+": line 1") {
+			t.Errorf("Load() error = %v; want prefix %q", err, path+": line 1")
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package configFile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseTOML parses the subset of TOML used for configuration files: tables,
+// dotted and quoted keys, strings, numbers, booleans and (multi-line) arrays.
+func ParseTOML(data []byte) (*Node, error) {
+	root := newMap(0)
+	current := root
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		num := i + 1
+		text := strings.TrimSpace(stripComment(lines[i]))
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[[") {
+			return nil, fmt.Errorf("line %d: arrays of tables are not supported", num)
+		}
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("line %d: unterminated table header", num)
+			}
+			keys, err := splitTOMLKey(text[1:len(text)-1], num)
+			if err != nil {
+				return nil, err
+			}
+			table, err := descend(root, keys, num)
+			if err != nil {
+				return nil, err
+			}
+			current = table
+			continue
+		}
+
+		eq := findUnquoted(text, func(s string, i int) bool { return s[i] == '=' })
+		if eq <= 0 {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", num)
+		}
+		keys, err := splitTOMLKey(text[:eq], num)
+		if err != nil {
+			return nil, err
+		}
+		raw := strings.TrimSpace(text[eq+1:])
+
+		for strings.HasPrefix(raw, "[") && !isBalanced(raw) {
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("line %d: unterminated array", num)
+			}
+			raw += " " + strings.TrimSpace(stripComment(lines[i]))
+		}
+
+		value, err := parseTOMLValue(raw, num)
+		if err != nil {
+			return nil, err
+		}
+		table, err := descend(current, keys[:len(keys)-1], num)
+		if err != nil {
+			return nil, err
+		}
+		if err := table.set(keys[len(keys)-1], value); err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
+
+func descend(node *Node, keys []string, line int) (*Node, error) {
+	for _, key := range keys {
+		child, exists := node.Fields[key]
+		if !exists {
+			child = newMap(line)
+			node.Keys = append(node.Keys, key)
+			node.Fields[key] = child
+		}
+		if child.Kind != MapNode {
+			return nil, fmt.Errorf("line %d: key %q is already defined as a value", line, key)
+		}
+		node = child
+	}
+	return node, nil
+}
+
+func splitTOMLKey(s string, line int) ([]string, error) {
+	var keys []string
+	for {
+		s = strings.TrimSpace(s)
+		i := findUnquoted(s, func(s string, i int) bool { return s[i] == '.' })
+		part := s
+		if i >= 0 {
+			part = strings.TrimSpace(s[:i])
+		}
+		if part == "" {
+			return nil, fmt.Errorf("line %d: empty key", line)
+		}
+		if part[0] == '"' || part[0] == '\'' {
+			unquoted, err := unquote(part, line)
+			if err != nil {
+				return nil, err
+			}
+			part = unquoted
+		} else if strings.ContainsAny(part, " \t\"'") {
+			return nil, fmt.Errorf("line %d: invalid key %q", line, part)
+		}
+		keys = append(keys, part)
+		if i < 0 {
+			return keys, nil
+		}
+		s = s[i+1:]
+	}
+}
+
+func isBalanced(s string) bool {
+	depth := 0
+	findUnquoted(s, func(s string, i int) bool {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		return false
+	})
+	return depth == 0
+}
+
+func parseTOMLValue(s string, line int) (*Node, error) {
+	if s == "" {
+		return nil, fmt.Errorf("line %d: missing value", line)
+	}
+	if strings.HasPrefix(s, "[") {
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("line %d: unterminated array", line)
+		}
+		items, err := splitList(s, line)
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Kind: ListNode, Line: line, Items: items}, nil
+	}
+	if strings.HasPrefix(s, "{") {
+		return nil, fmt.Errorf("line %d: inline tables are not supported", line)
+	}
+	if strings.HasPrefix(s, `"""`) || strings.HasPrefix(s, "'''") {
+		return nil, fmt.Errorf("line %d: multi-line strings are not supported", line)
+	}
+
+	value, err := parseScalar(s, line)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{Kind: ScalarNode, Line: line, Value: tomlNumber(s, value)}, nil
+}
+
+// tomlNumber drops the digit separators TOML allows in numbers such as 1_000.
+func tomlNumber(raw, value string) string {
+	if raw == "" || raw[0] == '"' || raw[0] == '\'' {
+		return value
+	}
+	if strings.Trim(raw, "0123456789_+-.") == "" {
+		return strings.ReplaceAll(value, "_", "")
+	}
+	return value
+}
@@ -0,0 +1,80 @@
+package configFile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "scalars and comments",
+			input: "# comment\ninput = \"src\" # trailing\nmax-file-size = 1_024\nverbose = true\n",
+			want:  map[string]string{"input": "src", "max-file-size": "1024", "verbose": "true"},
+		},
+		{
+			name:  "literal strings",
+			input: "ignore = 'C:\\path'\n",
+			want:  map[string]string{"ignore": "C:\\path"},
+		},
+		{
+			name:  "arrays",
+			input: "languages = [\"go\", \"js\"]\nignore = [\n  \"*.log\", # logs\n  \"vendor/\",\n]\n",
+			want:  map[string]string{"languages": "go,js", "ignore": "*.log,vendor/"},
+		},
+		{
+			name:  "tables and dotted keys",
+			input: "[profiles.review]\nlanguages = \"go\"\n[profiles.docs]\nlanguages = \"md\"\nextra.key = \"x\"\n",
+			want:  map[string]string{"profiles.review.languages": "go", "profiles.docs.languages": "md", "profiles.docs.extra.key": "x"},
+		},
+		{
+			name:  "quoted keys",
+			input: "[map]\n\".tf\" = \"hcl\"\n",
+			want:  map[string]string{"map..tf": "hcl"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ParseTOML([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("ParseTOML() error: %v", err)
+			}
+			if got := flatten(t, root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTOML() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"duplicate key", "input = \"a\"\ninput = \"b\"\n", "line 2: duplicate key \"input\""},
+		{"missing equals", "input\n", "line 1: expected \"key = value\""},
+		{"array of tables", "[[profiles]]\n", "line 1: arrays of tables"},
+		{"inline table", "profile = { a = 1 }\n", "line 1: inline tables"},
+		{"unterminated array", "ignore = [\n\"a\",\n", "line 1: unterminated array"},
+		{"table over value", "a = \"x\"\n[a]\n", "line 2: key \"a\" is already defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTOML([]byte(tt.input))
+			if err == nil {
+				t.Fatal("ParseTOML() should error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseTOML() error = %v; want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package configFile
+
+import (
+	"fmt"
+	"strings"
+)
+
+type yamlLine struct {
+	indent int
+	text   string
+	num    int
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// ParseYAML parses the subset of YAML used for configuration files: nested
+// block mappings, block and flow lists of scalars, quoted strings and comments.
+func ParseYAML(data []byte) (*Node, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text := stripComment(raw)
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{indent: len(text) - len(trimmed), text: trimmed, num: i + 1})
+	}
+
+	if len(p.lines) == 0 {
+		return newMap(0), nil
+	}
+	if isListItem(p.lines[0].text) {
+		return nil, fmt.Errorf("line %d: top level must be a mapping", p.lines[0].num)
+	}
+
+	root, err := p.parseBlock()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return root, nil
+}
+
+func isListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) parseBlock() (*Node, error) {
+	if isListItem(p.lines[p.pos].text) {
+		return p.parseList(p.lines[p.pos].indent)
+	}
+	return p.parseMap(p.lines[p.pos].indent)
+}
+
+func (p *yamlParser) parseMap(indent int) (*Node, error) {
+	node := newMap(p.lines[p.pos].num)
+
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && !isListItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		key, rest, err := splitYAMLKey(line)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+
+		var value *Node
+		switch {
+		case rest != "":
+			value, err = parseYAMLValue(rest, line.num)
+		case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+			value, err = p.parseBlock()
+		case p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isListItem(p.lines[p.pos].text):
+			value, err = p.parseList(indent)
+		default:
+			value = &Node{Kind: ScalarNode, Line: line.num}
+		}
+		if err != nil {
+			return nil, err
+		}
+		value.Line = line.num
+		if err := node.set(key, value); err != nil {
+			return nil, err
+		}
+
+		if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+		}
+	}
+
+	return node, nil
+}
+
+func (p *yamlParser) parseList(indent int) (*Node, error) {
+	node := &Node{Kind: ListNode, Line: p.lines[p.pos].num}
+
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isListItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		rest := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		p.pos++
+
+		if rest == "" || strings.Contains(rest, ": ") || strings.HasSuffix(rest, ":") {
+			return nil, fmt.Errorf("line %d: only scalar list items are supported", line.num)
+		}
+		value, err := parseYAMLValue(rest, line.num)
+		if err != nil {
+			return nil, err
+		}
+		node.Items = append(node.Items, value)
+
+		if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+		}
+	}
+
+	return node, nil
+}
+
+func splitYAMLKey(line yamlLine) (string, string, error) {
+	i := findUnquoted(line.text, func(s string, i int) bool {
+		return s[i] == ':' && (i == len(s)-1 || s[i+1] == ' ')
+	})
+	if i <= 0 {
+		return "", "", fmt.Errorf("line %d: expected \"key: value\"", line.num)
+	}
+
+	key := strings.TrimSpace(line.text[:i])
+	if key[0] == '"' || key[0] == '\'' {
+		unquoted, err := unquote(key, line.num)
+		if err != nil {
+			return "", "", err
+		}
+		key = unquoted
+	}
+	return key, strings.TrimSpace(line.text[i+1:]), nil
+}
+
+func parseYAMLValue(s string, line int) (*Node, error) {
+	if strings.HasPrefix(s, "[") {
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("line %d: unterminated list", line)
+		}
+		items, err := splitList(s, line)
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Kind: ListNode, Line: line, Items: items}, nil
+	}
+
+	value, err := parseScalar(s, line)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{Kind: ScalarNode, Line: line, Value: value}, nil
+}
@@ -0,0 +1,101 @@
+package configFile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "scalars and comments",
+			input: "# comment\ninput: src # trailing\nmax-file-size: 1024\n",
+			want:  map[string]string{"input": "src", "max-file-size": "1024"},
+		},
+		{
+			name:  "quoted values",
+			input: "output: \"out # not a comment.md\"\nignore: 'it''s'\n",
+			want:  map[string]string{"output": "out # not a comment.md", "ignore": "it's"},
+		},
+		{
+			name:  "flow list",
+			input: "languages: [go, \"js\", 'py']\n",
+			want:  map[string]string{"languages": "go,js,py"},
+		},
+		{
+			name:  "block list",
+			input: "ignore:\n  - \"*.log\"\n  - vendor/\n",
+			want:  map[string]string{"ignore": "*.log,vendor/"},
+		},
+		{
+			name:  "block list at same indentation",
+			input: "ignore:\n- a\n- b\ninput: .\n",
+			want:  map[string]string{"ignore": "a,b", "input": "."},
+		},
+		{
+			name:  "nested maps",
+			input: "profiles:\n  review:\n    languages: go\n  docs:\n    languages: md\n",
+			want:  map[string]string{"profiles.review.languages": "go", "profiles.docs.languages": "md"},
+		},
+		{
+			name:  "quoted keys",
+			input: "\"*.tf\": hcl\n",
+			want:  map[string]string{"*.tf": "hcl"},
+		},
+		{
+			name:  "empty value",
+			input: "output:\ninput: .\n",
+			want:  map[string]string{"output": "", "input": "."},
+		},
+		{
+			name:  "empty document",
+			input: "# nothing here\n",
+			want:  map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ParseYAML([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("ParseYAML() error: %v", err)
+			}
+			if got := flatten(t, root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseYAML() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"duplicate key", "input: a\ninput: b\n", "line 2: duplicate key \"input\""},
+		{"bad indentation", "input: a\n  output: b\n", "line 2: unexpected indentation"},
+		{"missing colon", "input\n", "line 1: expected \"key: value\""},
+		{"tab indentation", "profiles:\n\tdocs: x\n", "line 2: tabs"},
+		{"unterminated string", "input: \"abc\n", "line 1: unterminated string"},
+		{"top level list", "- a\n", "line 1: top level must be a mapping"},
+		{"map list items", "ignore:\n  - a: b\n", "line 2: only scalar list items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseYAML([]byte(tt.input))
+			if err == nil {
+				t.Fatal("ParseYAML() should error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseYAML() error = %v; want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
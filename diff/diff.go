@@ -0,0 +1,256 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type edit struct {
+	kind    opKind
+	oldLine int
+	newLine int
+	text    string
+}
+
+func Unified(oldName, newName, oldText, newText string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+
+	edits := lineEdits(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range buildHunks(edits, context) {
+		writeHunk(&sb, edits[h[0]:h[1]])
+	}
+	return sb.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func lineEdits(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []edit
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, edit{opEqual, i, i, a[i]})
+	}
+	for _, e := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		e.oldLine += prefix
+		e.newLine += prefix
+		edits = append(edits, e)
+	}
+	for i := suffix; i > 0; i-- {
+		edits = append(edits, edit{opEqual, len(a) - i, len(b) - i, a[len(a)-i]})
+	}
+	return edits
+}
+
+// maxEditDistance bounds the number of steps of myers, whose trace grows
+// with the square of the edit distance. Larger differences are shown as a
+// replacement of all lines.
+const maxEditDistance = 2000
+
+// myers computes a shortest edit script between a and b using the greedy
+// algorithm from Eugene Myers' "An O(ND) Difference Algorithm".
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	limit := n + m
+	if limit == 0 {
+		return nil
+	}
+
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	// trace[d] holds v[k] for k in [-d-1, d+1] before step d, the only
+	// diagonals backtrack reads.
+	var trace [][]int
+
+	for d := 0; d <= limit && d <= maxEditDistance; d++ {
+		window := make([]int, 2*d+3)
+		copy(window, v[offset-d-1:offset+d+2])
+		trace = append(trace, window)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+	return replaceAll(a, b)
+}
+
+func backtrack(trace [][]int, a, b []string) []edit {
+	x, y := len(a), len(b)
+	var reversed []edit
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		window := trace[d]
+		v := func(k int) int { return window[k+d+1] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, edit{opEqual, x, y, a[x]})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, edit{opInsert, x, prevY, b[prevY]})
+			} else {
+				reversed = append(reversed, edit{opDelete, prevX, y, a[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	edits := make([]edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
+
+// replaceAll deletes all lines of a and inserts all lines of b.
+func replaceAll(a, b []string) []edit {
+	edits := make([]edit, 0, len(a)+len(b))
+	for i, line := range a {
+		edits = append(edits, edit{opDelete, i, 0, line})
+	}
+	for j, line := range b {
+		edits = append(edits, edit{opInsert, len(a), j, line})
+	}
+	return edits
+}
+
+// buildHunks groups changes that are at most 2*context lines apart and returns
+// the [start, end) edit ranges of each hunk including surrounding context.
+func buildHunks(edits []edit, context int) [][2]int {
+	var hunks [][2]int
+	for i := 0; i < len(edits); i++ {
+		if edits[i].kind == opEqual {
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i + 1
+		for j := i + 1; j < len(edits); j++ {
+			if edits[j].kind == opEqual {
+				continue
+			}
+			if j-end > 2*context {
+				break
+			}
+			end = j + 1
+		}
+		i = end - 1
+
+		end += context
+		if end > len(edits) {
+			end = len(edits)
+		}
+		if n := len(hunks); n > 0 && hunks[n-1][1] >= start {
+			hunks[n-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+	return hunks
+}
+
+func writeHunk(sb *strings.Builder, edits []edit) {
+	oldStart, newStart := -1, -1
+	oldCount, newCount := 0, 0
+	for _, e := range edits {
+		if e.kind != opInsert {
+			if oldStart < 0 {
+				oldStart = e.oldLine
+			}
+			oldCount++
+		}
+		if e.kind != opDelete {
+			if newStart < 0 {
+				newStart = e.newLine
+			}
+			newCount++
+		}
+	}
+	if oldStart < 0 {
+		oldStart = edits[0].oldLine - 1
+	}
+	if newStart < 0 {
+		newStart = edits[0].newLine - 1
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, e := range edits {
+		prefix := " "
+		switch e.kind {
+		case opDelete:
+			prefix = "-"
+		case opInsert:
+			prefix = "+"
+		}
+		sb.WriteString(prefix + e.text)
+		if !strings.HasSuffix(e.text, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "identical texts",
+			oldText: "a\nb\n",
+			newText: "a\nb\n",
+			want:    "",
+		},
+		{
+			name:    "changed line",
+			oldText: "a\nb\nc\n",
+			newText: "a\nB\nc\n",
+			want:    "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:    "new file",
+			oldText: "",
+			newText: "a\nb\n",
+			want:    "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "deleted content",
+			oldText: "a\n",
+			newText: "",
+			want:    "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:    "missing trailing newline",
+			oldText: "a",
+			newText: "a\n",
+			want:    "--- old\n+++ new\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a\n",
+		},
+		{
+			name:    "distant changes produce separate hunks",
+			oldText: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			newText: "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- old\n+++ new\n" +
+				"@@ -1,2 +1,2 @@\n-1\n+one\n 2\n" +
+				"@@ -9,2 +9,2 @@\n 9\n-10\n+ten\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("old", "new", tt.oldText, tt.newText, 1)
+			if got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineEditsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{"insert in middle", "a\nb\nc\n", "a\nb\nx\nc\n"},
+		{"delete in middle", "a\nb\nc\n", "a\nc\n"},
+		{"reorder", "a\nb\nc\nd\n", "d\nc\nb\na\n"},
+		{"completely different", "a\nb\n", "x\ny\nz\n"},
+		{"many scattered changes", numberedLines("a", 3000, 7), numberedLines("a", 3000, 11)},
+		{"large rewrite", numberedLines("a", 6000, 0), numberedLines("b", 6000, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oldLines, newLines []string
+			for _, e := range lineEdits(splitLines(tt.a), splitLines(tt.b)) {
+				if e.kind != opInsert {
+					oldLines = append(oldLines, e.text)
+				}
+				if e.kind != opDelete {
+					newLines = append(newLines, e.text)
+				}
+			}
+			if got := strings.Join(oldLines, ""); got != tt.a {
+				t.Errorf("old side = %q; want %q", got, tt.a)
+			}
+			if got := strings.Join(newLines, ""); got != tt.b {
+				t.Errorf("new side = %q; want %q", got, tt.b)
+			}
+		})
+	}
+}
+
+// numberedLines returns n lines named after prefix, with every line whose
+// number is a multiple of every changed.
+func numberedLines(prefix string, n, every int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if every > 0 && i%every == 0 {
+			fmt.Fprintf(&b, "changed %d/%d\n", i, every)
+		} else {
+			fmt.Fprintf(&b, "%s %d\n", prefix, i)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package dumpParser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Elided follows the fence language of blocks whose content leaves out
+// parts of the file, such as outlines and stripped comments.
+const Elided = "elided"
+
+type FileBlock struct {
+	Path     string
+	Language string
+	Content  string
+	Line     int
+	// Elided is set for blocks marked as elided, which cannot be written
+	// back onto the file.
+	Elided bool
+}
+
+func Parse(r io.Reader) ([]FileBlock, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading input: %w", err)
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	var blocks []FileBlock
+	for i := 0; i < len(lines); i++ {
+		if !isHeader(lines[i]) {
+			continue
+		}
+
+		switch {
+		case i+1 < len(lines) && strings.HasPrefix(lines[i+1], "```"):
+		case isNote(lines, i+1):
+			i += 2
+			continue
+		default:
+			i = unfencedEnd(lines, i+1) - 1
+			continue
+		}
+
+		end := findClosingFence(lines, i+2)
+		if end < 0 {
+			return nil, fmt.Errorf("line %d: unterminated code block for %s", i+1, headerPath(lines[i]))
+		}
+
+		content := ""
+		if end > i+2 {
+			content = strings.Join(lines[i+2:end], "\n") + "\n"
+		}
+
+		info := strings.Fields(strings.TrimPrefix(lines[i+1], "```"))
+		block := FileBlock{Path: headerPath(lines[i]), Content: content, Line: i + 1}
+		if len(info) > 0 {
+			block.Language = info[0]
+		}
+		for _, word := range info[1:] {
+			block.Elided = block.Elided || word == Elided
+		}
+		blocks = append(blocks, block)
+		i = end
+	}
+
+	return blocks, nil
+}
+
+func headerPath(line string) string {
+	return strings.TrimSpace(strings.TrimPrefix(line, "# "))
+}
+
+func isHeader(line string) bool {
+	return strings.HasPrefix(line, "# ") && headerPath(line) != ""
+}
+
+// isNote reports whether an entry without content starts at from: a blank
+// line and an italic note such as "_symlink → target_" or "_Vendored
+// directory, content omitted._".
+func isNote(lines []string, from int) bool {
+	if from+1 >= len(lines) || lines[from] != "" {
+		return false
+	}
+	note := lines[from+1]
+	return len(note) > 2 && strings.HasPrefix(note, "_") && strings.HasSuffix(note, "_")
+}
+
+// unfencedEnd returns the index of the header that follows the Markdown
+// content starting at from. Markdown files are written without a fence and
+// end with a newline and a blank line, so only a header after two blank
+// lines starts the next entry; headings of the Markdown file are skipped.
+func unfencedEnd(lines []string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if isHeader(lines[i]) && i-2 >= from && lines[i-1] == "" && lines[i-2] == "" {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// findClosingFence returns the index of the fence that ends the block starting
+// at from. A bare ``` line only counts when it is followed by a blank line and
+// then the header of the next entry or the end of the document, so fences
+// inside the file content itself are left alone.
+func findClosingFence(lines []string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if lines[i] != "```" {
+			continue
+		}
+		if isDocumentTail(lines, i+1) {
+			return i
+		}
+		if i+2 < len(lines) && lines[i+1] == "" && isHeader(lines[i+2]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isDocumentTail(lines []string, from int) bool {
+	for _, line := range lines[from:] {
+		if strings.TrimSpace(line) != "" {
+			return false
+		}
+	}
+	return true
+}
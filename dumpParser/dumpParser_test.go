@@ -0,0 +1,110 @@
+package dumpParser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []FileBlock
+	}{
+		{
+			name:  "single block",
+			input: "# main.go\n```go\npackage main\n```\n\n",
+			want: []FileBlock{
+				{Path: "main.go", Language: "go", Content: "package main\n", Line: 1},
+			},
+		},
+		{
+			name:  "multiple blocks",
+			input: "# a.go\n```go\npackage a\n```\n\n# sub/b.py\n```py\nprint(1)\n```\n\n",
+			want: []FileBlock{
+				{Path: "a.go", Language: "go", Content: "package a\n", Line: 1},
+				{Path: "sub/b.py", Language: "py", Content: "print(1)\n", Line: 6},
+			},
+		},
+		{
+			name:  "empty file",
+			input: "# empty.go\n```go\n```\n\n",
+			want: []FileBlock{
+				{Path: "empty.go", Language: "go", Content: "", Line: 1},
+			},
+		},
+		{
+			name:  "fence inside content is kept",
+			input: "# gen.go\n```go\nconst s = `\n```\n`\n```\n\n# other.go\n```go\npackage other\n```\n",
+			want: []FileBlock{
+				{Path: "gen.go", Language: "go", Content: "const s = `\n```\n`\n", Line: 1},
+				{Path: "other.go", Language: "go", Content: "package other\n", Line: 8},
+			},
+		},
+		{
+			name:  "unfenced markdown sections are skipped",
+			input: "# README.md\nSome text\n\n\n# main.go\n```go\npackage main\n```\n\n",
+			want: []FileBlock{
+				{Path: "main.go", Language: "go", Content: "package main\n", Line: 5},
+			},
+		},
+		{
+			name:  "fenced blocks in markdown are skipped",
+			input: "# a.go\n```go\npackage a\n```\n\n# README.md\n# Install\n```sh\nmake\n```\n\n# Usage\n\nRun it.\n\n\n# b.go\n```go\npackage b\n```\n\n",
+			want: []FileBlock{
+				{Path: "a.go", Language: "go", Content: "package a\n", Line: 1},
+				{Path: "b.go", Language: "go", Content: "package b\n", Line: 17},
+			},
+		},
+		{
+			name:  "summaries and symlinks are skipped",
+			input: "# src/b.go\n```go\npackage b\n```\n\n# vendor/\n\n_Vendored directory, content omitted._\n\n# link.go\n\n_symlink → src/b.go_\n\n# y.go\n```go\npackage y\n```\n\n",
+			want: []FileBlock{
+				{Path: "src/b.go", Language: "go", Content: "package b\n", Line: 1},
+				{Path: "y.go", Language: "go", Content: "package y\n", Line: 14},
+			},
+		},
+		{
+			name:  "elided blocks are marked",
+			input: "# a.go\n```go elided\nfunc A() int\n```\n\n",
+			want: []FileBlock{
+				{Path: "a.go", Language: "go", Content: "func A() int\n", Line: 1, Elided: true},
+			},
+		},
+		{
+			name:  "crlf line endings",
+			input: "# main.go\r\n```go\r\npackage main\r\n```\r\n",
+			want: []FileBlock{
+				{Path: "main.go", Language: "go", Content: "package main\n", Line: 1},
+			},
+		},
+		{
+			name:  "no blocks",
+			input: "just some text\n",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v; want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnterminatedBlock(t *testing.T) {
+	_, err := Parse(strings.NewReader("# main.go\n```go\npackage main\n"))
+	if err == nil {
+		t.Fatal("Parse() should error for unterminated block")
+	}
+	if !strings.Contains(err.Error(), "main.go") {
+		t.Errorf("error should mention the file, got: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package generatedCode
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const headerMaxBytes = 8192
+
+// generatedHeader is the convention from https://go.dev/s/generatedcode,
+// accepted in the comment syntax of other languages as well.
+var generatedHeader = regexp.MustCompile(`^(?://|#|--|;|/\*|\*|<!--)\s*Code generated .* DO NOT EDIT\.`)
+
+var commentPrefixes = []string{"//", "#", "--", ";", "/*", "*", "<!--", "-->"}
+
+var vendoredDirs = map[string]bool{
+	"bower_components": true,
+	"jspm_packages":    true,
+	"node_modules":     true,
+	"third_party":      true,
+	"third-party":      true,
+	"thirdparty":       true,
+	"vendor":           true,
+	"vendors":          true,
+	"Pods":             true,
+	"Carthage":         true,
+}
+
+// IsVendoredDir reports whether a directory name is commonly used for
+// vendored dependencies.
+func IsVendoredDir(name string) bool {
+	return vendoredDirs[name]
+}
+
+// IsGeneratedFile reports whether the file starts with a
+// "Code generated ... DO NOT EDIT." header.
+func IsGeneratedFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	head := make([]byte, headerMaxBytes)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return IsGenerated(head[:n]), nil
+}
+
+// IsGenerated looks for the header before the first line that is neither
+// blank nor a comment.
+func IsGenerated(head []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(head))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if generatedHeader.MatchString(line) {
+			return true
+		}
+		if !isComment(line) {
+			return false
+		}
+	}
+	return false
+}
+
+func isComment(line string) bool {
+	for _, prefix := range commentPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package generatedCode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"go header", "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage api\n", true},
+		{"header after build tags", "//go:build linux\n\n// Code generated by mockgen. DO NOT EDIT.\npackage mocks\n", true},
+		{"hash comment", "# Code generated by tool. DO NOT EDIT.\nkey: value\n", true},
+		{"block comment", "/* Code generated by tool. DO NOT EDIT. */\nexport {}\n", true},
+		{"html comment", "<!-- Code generated by tool. DO NOT EDIT. -->\n<p></p>\n", true},
+		{"header after code", "package api\n\n// Code generated by protoc-gen-go. DO NOT EDIT.\n", false},
+		{"missing period", "// Code generated by tool. DO NOT EDIT\npackage api\n", false},
+		{"plain comment", "// Package api implements the API.\npackage api\n", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsGenerated([]byte(tt.content)); got != tt.want {
+				t.Errorf("IsGenerated(%q) = %v; want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGeneratedFile(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "api.pb.go")
+	os.WriteFile(path, []byte("// Code generated by protoc-gen-go. DO NOT EDIT.\npackage api\n"), 0644)
+
+	generated, err := IsGeneratedFile(path)
+	if err != nil {
+		t.Fatalf("IsGeneratedFile() error: %v", err)
+	}
+	if !generated {
+		t.Error("IsGeneratedFile() should detect the header")
+	}
+
+	if _, err := IsGeneratedFile(filepath.Join(tempDir, "missing.go")); err == nil {
+		t.Error("IsGeneratedFile() should error for a missing file")
+	}
+}
+
+func TestIsVendoredDir(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"vendor", true},
+		{"node_modules", true},
+		{"third_party", true},
+		{"Pods", true},
+		{"src", false},
+		{"Vendor", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsVendoredDir(tt.name); got != tt.want {
+				t.Errorf("IsVendoredDir(%q) = %v; want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package gitAttributes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const FileName = ".gitattributes"
+
+// Attributes maps attribute names to "true" when set, "false" when unset
+// (-name) or to their value (name=value). Unspecified attributes are absent.
+type Attributes map[string]string
+
+func (a Attributes) IsSet(name string) bool {
+	return a[name] == "true"
+}
+
+func (a Attributes) IsUnset(name string) bool {
+	return a[name] == "false"
+}
+
+type attribute struct {
+	name        string
+	value       string
+	unspecified bool
+}
+
+type rule struct {
+	pattern    string
+	attributes []attribute
+}
+
+var macros = map[string][]attribute{
+	"binary": {{name: "binary", value: "true"}, {name: "diff", value: "false"}, {name: "merge", value: "false"}, {name: "text", value: "false"}},
+}
+
+// Matcher looks up the attributes of paths below root, loading the
+// .gitattributes file of every directory on first use.
+type Matcher struct {
+	root  string
+	rules map[string][]rule
+}
+
+func NewMatcher(root string) *Matcher {
+	return &Matcher{root: root, rules: make(map[string][]rule)}
+}
+
+// Attributes returns the attributes of relPath. Rules of deeper
+// .gitattributes files and later lines take precedence.
+func (m *Matcher) Attributes(relPath string) (Attributes, error) {
+	relPath = filepath.ToSlash(relPath)
+	result := make(Attributes)
+
+	dir := ""
+	rest := relPath
+	for {
+		rules, err := m.load(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rules {
+			if matches(r.pattern, rest) {
+				apply(result, r.attributes)
+			}
+		}
+
+		i := strings.Index(rest, "/")
+		if i < 0 {
+			return result, nil
+		}
+		dir = path.Join(dir, rest[:i])
+		rest = rest[i+1:]
+	}
+}
+
+func (m *Matcher) load(dir string) ([]rule, error) {
+	if rules, ok := m.rules[dir]; ok {
+		return rules, nil
+	}
+	file := filepath.Join(m.root, filepath.FromSlash(dir), FileName)
+	rules, err := parseFile(file)
+	if err != nil {
+		return nil, err
+	}
+	m.rules[dir] = rules
+	return rules, nil
+}
+
+func parseFile(file string) ([]rule, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+	defer f.Close()
+
+	var rules []rule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if r, ok := parseLine(scanner.Text()); ok {
+			rules = append(rules, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+	return rules, nil
+}
+
+// parseLine parses "pattern attr1 -attr2 !attr3 attr4=value". Comments,
+// macro definitions and negative patterns, which git rejects, are skipped.
+func parseLine(line string) (rule, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[attr]") {
+		return rule{}, false
+	}
+
+	var pattern string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return rule{}, false
+		}
+		pattern = line[1 : end+1]
+		line = line[end+2:]
+	} else {
+		fields := strings.Fields(line)
+		pattern = fields[0]
+		line = strings.TrimPrefix(line, pattern)
+	}
+	if pattern == "" || strings.HasPrefix(pattern, "!") {
+		return rule{}, false
+	}
+
+	r := rule{pattern: pattern}
+	for _, field := range strings.Fields(line) {
+		switch {
+		case strings.HasPrefix(field, "-"):
+			r.attributes = append(r.attributes, attribute{name: field[1:], value: "false"})
+		case strings.HasPrefix(field, "!"):
+			r.attributes = append(r.attributes, attribute{name: field[1:], unspecified: true})
+		default:
+			name, value, hasValue := strings.Cut(field, "=")
+			if !hasValue {
+				value = "true"
+			}
+			if expansion, isMacro := macros[name]; isMacro && !hasValue {
+				r.attributes = append(r.attributes, expansion...)
+				continue
+			}
+			r.attributes = append(r.attributes, attribute{name: name, value: value})
+		}
+	}
+	return r, true
+}
+
+func apply(result Attributes, attributes []attribute) {
+	for _, a := range attributes {
+		if a.unspecified {
+			delete(result, a.name)
+			continue
+		}
+		result[a.name] = a.value
+	}
+}
+
+// matches follows the gitattributes rules: a pattern without a slash matches
+// the base name at any depth, any other pattern the path relative to the
+// directory of the .gitattributes file, with ** matching any number of
+// directories.
+func matches(pattern, relPath string) bool {
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		matched, _ := path.Match(pattern, path.Base(relPath))
+		return matched
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				return len(name) > 0
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
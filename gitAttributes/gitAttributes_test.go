@@ -0,0 +1,109 @@
+package gitAttributes
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"base name at root", "*.pb.go", "api.pb.go", true},
+		{"base name at any depth", "*.pb.go", "proto/v1/api.pb.go", true},
+		{"base name mismatch", "*.pb.go", "api.go", false},
+		{"anchored path", "docs/*.md", "docs/intro.md", true},
+		{"anchored path not nested", "docs/*.md", "docs/sub/intro.md", false},
+		{"anchored path only at its directory", "docs/*.md", "src/docs/intro.md", false},
+		{"leading slash", "/gen.go", "gen.go", true},
+		{"leading slash not nested", "/gen.go", "pkg/gen.go", false},
+		{"trailing globstar", "vendor/**", "vendor/lib/a.go", true},
+		{"trailing globstar not the directory", "vendor/**", "vendor", false},
+		{"leading globstar", "**/mocks/*.go", "internal/mocks/db.go", true},
+		{"leading globstar at root", "**/mocks/*.go", "mocks/db.go", true},
+		{"inner globstar", "a/**/z.txt", "a/b/c/z.txt", true},
+		{"inner globstar zero directories", "a/**/z.txt", "a/z.txt", true},
+		{"directory pattern does not match files", "vendor/", "vendor/a.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matches(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("matches(%q, %q) = %v; want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   rule
+		wantOK bool
+	}{
+		{"*.go text diff=golang", rule{"*.go", []attribute{{name: "text", value: "true"}, {name: "diff", value: "golang"}}}, true},
+		{"*.png -text !eol", rule{"*.png", []attribute{{name: "text", value: "false"}, {name: "eol", unspecified: true}}}, true},
+		{"*.bin binary", rule{"*.bin", macros["binary"]}, true},
+		{`"with space.txt" export-ignore`, rule{"with space.txt", []attribute{{name: "export-ignore", value: "true"}}}, true},
+		{"# comment", rule{}, false},
+		{"   ", rule{}, false},
+		{"[attr]generated linguist-generated", rule{}, false},
+		{"!negated text", rule{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := parseLine(tt.line)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLine(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMatcherAttributes(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "api", "gen"), 0755)
+	os.WriteFile(filepath.Join(tempDir, FileName), []byte("*.pb.go linguist-generated\n*.go diff=golang\nthird_party/** linguist-vendored\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "api", FileName), []byte("gen/*.pb.go -linguist-generated\n*.go !diff\n"), 0644)
+
+	tests := []struct {
+		path string
+		want Attributes
+	}{
+		{"main.go", Attributes{"diff": "golang"}},
+		{"model.pb.go", Attributes{"linguist-generated": "true", "diff": "golang"}},
+		{filepath.Join("api", "gen", "api.pb.go"), Attributes{"linguist-generated": "false"}},
+		{filepath.Join("api", "other.pb.go"), Attributes{"linguist-generated": "true"}},
+		{filepath.Join("third_party", "lib", "x.c"), Attributes{"linguist-vendored": "true"}},
+		{"README.md", Attributes{}},
+	}
+
+	m := NewMatcher(tempDir)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := m.Attributes(tt.path)
+			if err != nil {
+				t.Fatalf("Attributes() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Attributes(%q) = %v; want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("set and unset", func(t *testing.T) {
+		a := Attributes{"linguist-generated": "true", "text": "false", "diff": "golang"}
+		if !a.IsSet("linguist-generated") || a.IsSet("diff") || a.IsSet("missing") {
+			t.Error("IsSet() should only be true for set attributes")
+		}
+		if !a.IsUnset("text") || a.IsUnset("linguist-generated") || a.IsUnset("missing") {
+			t.Error("IsUnset() should only be true for unset attributes")
+		}
+	})
+}
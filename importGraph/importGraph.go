@@ -0,0 +1,250 @@
+package importGraph
+
+import (
+	"bufio"
+	"container/heap"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Module is a Go module: its root directory and module path.
+type Module struct {
+	Root string
+	Path string
+}
+
+// FindModule locates the go.mod file in dir or the closest parent directory.
+func FindModule(dir string) (Module, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Module{}, err
+	}
+	for {
+		path, err := readModulePath(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			return Module{Root: dir, Path: path}, nil
+		}
+		if !os.IsNotExist(err) {
+			return Module{}, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Module{}, errors.New("no go.mod found")
+		}
+		dir = parent
+	}
+}
+
+func readModulePath(goMod string) (string, error) {
+	file, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			if path, err := strconv.Unquote(fields[1]); err == nil {
+				return path, nil
+			}
+			return fields[1], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading %s: %w", goMod, err)
+	}
+	return "", fmt.Errorf("no module directive in %s", goMod)
+}
+
+// Dependencies returns, for the directory of every Go file, the directories
+// of the packages of the module it imports. Imports of other modules and
+// files that fail to parse are ignored.
+func (m Module) Dependencies(goFiles []string) map[string][]string {
+	deps := map[string][]string{}
+	seen := map[[2]string]bool{}
+	fset := token.NewFileSet()
+	for _, path := range goFiles {
+		dir := filepath.Dir(path)
+		if _, ok := deps[dir]; !ok {
+			deps[dir] = nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			target, ok := m.dir(importPath)
+			if !ok || target == dir || seen[[2]string{dir, target}] {
+				continue
+			}
+			seen[[2]string{dir, target}] = true
+			deps[dir] = append(deps[dir], target)
+		}
+	}
+	return deps
+}
+
+// dir maps an import path of the module to its directory.
+func (m Module) dir(importPath string) (string, bool) {
+	if importPath == m.Path {
+		return m.Root, true
+	}
+	if rel := strings.TrimPrefix(importPath, m.Path+"/"); rel != importPath {
+		return filepath.Join(m.Root, filepath.FromSlash(rel)), true
+	}
+	return "", false
+}
+
+// Sort orders nodes so that every node comes after the nodes it depends
+// on. Nodes in a cycle are kept together in their input order and also
+// returned as a cycle. Among independent nodes the input order is kept.
+// Dependencies on nodes that are not listed are ignored.
+func Sort(nodes []string, deps map[string][]string) (order []string, cycles [][]string) {
+	index := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		index[n] = i
+	}
+
+	components := stronglyConnected(nodes, deps, index)
+	componentOf := make([]int, len(nodes))
+	for c, members := range components {
+		for _, i := range members {
+			componentOf[i] = c
+		}
+		if len(members) > 1 {
+			cycle := make([]string, len(members))
+			for k, i := range members {
+				cycle[k] = nodes[i]
+			}
+			cycles = append(cycles, cycle)
+		}
+	}
+
+	// remaining counts the unwritten components each component depends on,
+	// dependents is the reverse relation.
+	remaining := make([]int, len(components))
+	dependents := make([][]int, len(components))
+	for i, n := range nodes {
+		linked := map[int]bool{}
+		for _, dep := range deps[n] {
+			j, ok := index[dep]
+			if !ok || componentOf[j] == componentOf[i] || linked[componentOf[j]] {
+				continue
+			}
+			linked[componentOf[j]] = true
+			remaining[componentOf[i]]++
+			dependents[componentOf[j]] = append(dependents[componentOf[j]], componentOf[i])
+		}
+	}
+
+	ready := &componentQueue{components: components}
+	for c := range components {
+		if remaining[c] == 0 {
+			heap.Push(ready, c)
+		}
+	}
+	for ready.Len() > 0 {
+		c := heap.Pop(ready).(int)
+		for _, i := range components[c] {
+			order = append(order, nodes[i])
+		}
+		for _, d := range dependents[c] {
+			remaining[d]--
+			if remaining[d] == 0 {
+				heap.Push(ready, d)
+			}
+		}
+	}
+	return order, cycles
+}
+
+// stronglyConnected returns the strongly connected components of the
+// graph with Tarjan's algorithm, each listing node indexes in input order.
+func stronglyConnected(nodes []string, deps map[string][]string, index map[string]int) [][]int {
+	var (
+		components [][]int
+		stack      []int
+		onStack    = make([]bool, len(nodes))
+		order      = make([]int, len(nodes))
+		low        = make([]int, len(nodes))
+		counter    = 0
+		visit      func(i int)
+	)
+	for i := range order {
+		order[i] = -1
+	}
+
+	visit = func(i int) {
+		order[i], low[i] = counter, counter
+		counter++
+		stack = append(stack, i)
+		onStack[i] = true
+		for _, dep := range deps[nodes[i]] {
+			j, ok := index[dep]
+			if !ok {
+				continue
+			}
+			if order[j] < 0 {
+				visit(j)
+				if low[j] < low[i] {
+					low[i] = low[j]
+				}
+			} else if onStack[j] && order[j] < low[i] {
+				low[i] = order[j]
+			}
+		}
+		if low[i] != order[i] {
+			return
+		}
+		var members []int
+		for {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[j] = false
+			members = append(members, j)
+			if j == i {
+				break
+			}
+		}
+		sort.Ints(members)
+		components = append(components, members)
+	}
+
+	for i := range nodes {
+		if order[i] < 0 {
+			visit(i)
+		}
+	}
+	return components
+}
+
+// componentQueue pops the component whose first node comes first.
+type componentQueue struct {
+	components [][]int
+	items      []int
+}
+
+func (q *componentQueue) Len() int { return len(q.items) }
+func (q *componentQueue) Less(i, j int) bool {
+	return q.components[q.items[i]][0] < q.components[q.items[j]][0]
+}
+func (q *componentQueue) Swap(i, j int)      { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *componentQueue) Push(x interface{}) { q.items = append(q.items, x.(int)) }
+func (q *componentQueue) Pop() interface{} {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
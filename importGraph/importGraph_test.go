@@ -0,0 +1,103 @@
+package importGraph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodes      []string
+		deps       map[string][]string
+		wantOrder  []string
+		wantCycles [][]string
+	}{
+		{
+			name:      "independent nodes keep input order",
+			nodes:     []string{"a", "b", "c"},
+			wantOrder: []string{"a", "b", "c"},
+		},
+		{
+			name:      "dependencies first",
+			nodes:     []string{"cmd", "lib", "util"},
+			deps:      map[string][]string{"cmd": {"lib"}, "lib": {"util"}},
+			wantOrder: []string{"util", "lib", "cmd"},
+		},
+		{
+			name:      "unrelated nodes stay early",
+			nodes:     []string{"a", "docs", "b"},
+			deps:      map[string][]string{"a": {"b"}},
+			wantOrder: []string{"docs", "b", "a"},
+		},
+		{
+			name:      "unknown dependencies are ignored",
+			nodes:     []string{"a", "b"},
+			deps:      map[string][]string{"a": {"external"}},
+			wantOrder: []string{"a", "b"},
+		},
+		{
+			name:       "cycles are kept together",
+			nodes:      []string{"a", "b", "c", "d"},
+			deps:       map[string][]string{"a": {"c"}, "c": {"a", "d"}, "b": {"a"}},
+			wantOrder:  []string{"d", "a", "c", "b"},
+			wantCycles: [][]string{{"a", "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, cycles := Sort(tt.nodes, tt.deps)
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("Sort() order = %v; want %v", order, tt.wantOrder)
+			}
+			if !reflect.DeepEqual(cycles, tt.wantCycles) {
+				t.Errorf("Sort() cycles = %v; want %v", cycles, tt.wantCycles)
+			}
+		})
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.18\n",
+		"main.go":        "package main\n\nimport (\n\t\"fmt\"\n\t\"example.com/app/lib\"\n\t\"example.com/other\"\n)\n",
+		"lib/lib.go":     "package lib\n\nimport \"example.com/app/lib/util\"\n",
+		"lib/lib2.go":    "package lib\n\nimport util \"example.com/app/lib/util\"\n",
+		"lib/util/u.go":  "package util\n",
+		"broken/main.go": "package broken\n\nimport (\n",
+	}
+	var goFiles []string
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Ext(name) == ".go" {
+			goFiles = append(goFiles, path)
+		}
+	}
+
+	module, err := FindModule(filepath.Join(root, "lib", "util"))
+	if err != nil {
+		t.Fatalf("FindModule() error = %v", err)
+	}
+	if module.Path != "example.com/app" || module.Root != root {
+		t.Errorf("FindModule() = %+v; want example.com/app at %s", module, root)
+	}
+
+	deps := module.Dependencies(goFiles)
+	want := map[string][]string{
+		root:                               {filepath.Join(root, "lib")},
+		filepath.Join(root, "lib"):         {filepath.Join(root, "lib", "util")},
+		filepath.Join(root, "lib", "util"): nil,
+		filepath.Join(root, "broken"):      nil,
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("Dependencies() = %v; want %v", deps, want)
+	}
+}
@@ -0,0 +1,261 @@
+package importGraph
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsImport     = regexp.MustCompile(`(?:\bfrom\s*|\bimport\s*\(?\s*|\brequire\s*\(\s*)["']([^"'\n]+)["']`)
+	pyImport     = regexp.MustCompile(`(?m)^[ \t]*import[ \t]+([^\n#;]+)`)
+	pyFromImport = regexp.MustCompile(`(?m)^[ \t]*from[ \t]+(\.*[\w.]*)[ \t]+import[ \t]+(\([^)]*\)|[^\n#;]*)`)
+)
+
+var jsExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", ".mts", ".cts"}
+
+// Resolver finds the files below a root directory imported by other files
+// below it: Go packages of the module, JavaScript and TypeScript relative
+// imports and Python modules.
+type Resolver struct {
+	root   string
+	module *Module
+	fset   *token.FileSet
+}
+
+// NewResolver returns a Resolver for the files below root. Go imports are
+// resolved when root is inside a Go module.
+func NewResolver(root string) *Resolver {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	r := &Resolver{root: root, fset: token.NewFileSet()}
+	if module, err := FindModule(root); err == nil {
+		r.module = &module
+	}
+	return r
+}
+
+// Imports returns the files imported by the file at relPath, relative to
+// the root. Imports that cannot be resolved to a file below the root are
+// ignored.
+func (r *Resolver) Imports(relPath string) ([]string, error) {
+	path := filepath.Join(r.root, relPath)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var imports []string
+	switch ext := filepath.Ext(relPath); {
+	case ext == ".go":
+		imports = r.goImports(path, content)
+	case ext == ".py":
+		imports = r.pythonImports(filepath.Dir(path), content)
+	case isJSExtension(ext):
+		imports = r.jsImports(filepath.Dir(path), content)
+	}
+
+	var files []string
+	seen := map[string]bool{}
+	for _, file := range imports {
+		rel, err := filepath.Rel(r.root, file)
+		if err != nil || rel == relPath || seen[rel] || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		seen[rel] = true
+		files = append(files, rel)
+	}
+	return files, nil
+}
+
+// Reachable returns the files reached from starts by following imports at
+// most depth times, or without limit if depth is negative. The starts must
+// be files, relative to the root.
+func (r *Resolver) Reachable(starts []string, depth int) (map[string]bool, error) {
+	reached := map[string]bool{}
+	var current []string
+	for _, start := range starts {
+		start = filepath.Clean(start)
+		if !isFile(filepath.Join(r.root, start)) {
+			return nil, fmt.Errorf("focus file %s not found in %s", start, r.root)
+		}
+		if !reached[start] {
+			reached[start] = true
+			current = append(current, start)
+		}
+	}
+
+	for level := 0; len(current) > 0 && (depth < 0 || level < depth); level++ {
+		var next []string
+		for _, relPath := range current {
+			imports, err := r.Imports(relPath)
+			if err != nil {
+				return nil, err
+			}
+			for _, imported := range imports {
+				if !reached[imported] {
+					reached[imported] = true
+					next = append(next, imported)
+				}
+			}
+		}
+		current = next
+	}
+	return reached, nil
+}
+
+// goImports returns the non-test Go files of the module packages imported
+// by the Go file at path.
+func (r *Resolver) goImports(path string, content []byte) []string {
+	if r.module == nil {
+		return nil
+	}
+	file, err := parser.ParseFile(r.fset, path, content, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		dir, ok := r.module.dir(importPath)
+		if !ok {
+			continue
+		}
+		dirEntries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range dirEntries {
+			name := e.Name()
+			if !e.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+				files = append(files, filepath.Join(dir, name))
+			}
+		}
+	}
+	return files
+}
+
+// jsImports resolves the relative imports, re-exports and requires of a
+// JavaScript or TypeScript file in dir. Import paths may omit the extension
+// or name a directory with an index file, and TypeScript files may be
+// imported with a .js extension.
+func (r *Resolver) jsImports(dir string, content []byte) []string {
+	var files []string
+	for _, m := range jsImport.FindAllSubmatch(content, -1) {
+		spec := string(m[1])
+		if !strings.HasPrefix(spec, "./") && !strings.HasPrefix(spec, "../") {
+			continue
+		}
+		base := filepath.Join(dir, filepath.FromSlash(spec))
+		candidates := []string{base}
+		for _, ext := range jsExtensions {
+			candidates = append(candidates, base+ext)
+		}
+		for _, ext := range jsExtensions {
+			candidates = append(candidates, filepath.Join(base, "index"+ext))
+		}
+		if ext := filepath.Ext(base); ext == ".js" || ext == ".jsx" || ext == ".mjs" {
+			trimmed := strings.TrimSuffix(base, ext)
+			candidates = append(candidates, trimmed+".ts", trimmed+".tsx", trimmed+".mts")
+		}
+		if file, ok := firstFile(candidates); ok {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// pythonImports resolves the imports of a Python file in dir. Relative
+// imports are resolved from dir, absolute ones from the root and then from
+// dir. Names imported from a package may be modules themselves.
+func (r *Resolver) pythonImports(dir string, content []byte) []string {
+	var files []string
+	resolve := func(module string, bases []string) {
+		for _, base := range bases {
+			if file, ok := pythonModule(base, module); ok {
+				files = append(files, file)
+				return
+			}
+		}
+	}
+
+	for _, m := range pyImport.FindAllSubmatch(content, -1) {
+		for _, name := range importedNames(string(m[1])) {
+			resolve(name, []string{r.root, dir})
+		}
+	}
+
+	for _, m := range pyFromImport.FindAllSubmatch(content, -1) {
+		module := string(m[1])
+		bases := []string{r.root, dir}
+		if dots := len(module) - len(strings.TrimLeft(module, ".")); dots > 0 {
+			base := dir
+			for i := 1; i < dots; i++ {
+				base = filepath.Dir(base)
+			}
+			bases = []string{base}
+			module = module[dots:]
+		}
+		if module != "" {
+			resolve(module, bases)
+		}
+		for _, name := range importedNames(string(m[2])) {
+			if module != "" {
+				name = module + "." + name
+			}
+			resolve(name, bases)
+		}
+	}
+	return files
+}
+
+// importedNames splits "a as b, (c,\n d)" into the imported names a, c and d.
+func importedNames(list string) []string {
+	list = strings.Trim(strings.TrimSpace(list), "()")
+	var names []string
+	for _, part := range strings.Split(list, ",") {
+		fields := strings.Fields(part)
+		if len(fields) > 0 && fields[0] != "*" && fields[0] != "\\" {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
+
+// pythonModule returns the file of the dotted module name below base.
+func pythonModule(base, module string) (string, bool) {
+	path := filepath.Join(base, filepath.FromSlash(strings.ReplaceAll(module, ".", "/")))
+	return firstFile([]string{path + ".py", filepath.Join(path, "__init__.py")})
+}
+
+func firstFile(candidates []string) (string, bool) {
+	for _, candidate := range candidates {
+		if isFile(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func isJSExtension(ext string) bool {
+	for _, e := range jsExtensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
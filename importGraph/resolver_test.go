@@ -0,0 +1,108 @@
+package importGraph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestResolverImports(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"go.mod":                "module example.com/app\n",
+		"handler.go":            "package main\n\nimport (\n\t\"net/http\"\n\t\"example.com/app/store\"\n)\n",
+		"store/store.go":        "package store\n",
+		"store/cache.go":        "package store\n",
+		"store/store_test.go":   "package store\n",
+		"web/app.ts":            "import { a } from './lib';\nimport b from \"../web/util.js\";\nexport * from './widgets';\nimport 'react';\nconst c = require('./c');\n",
+		"web/lib.ts":            "",
+		"web/util.ts":           "",
+		"web/widgets/index.tsx": "",
+		"web/c.js":              "",
+		"py/app.py":             "import os\nimport py.models as m, py.views\nfrom . import helpers\nfrom .db import (\n    connect,\n    session,\n)\nfrom ..go import mod\n",
+		"py/models.py":          "",
+		"py/views/__init__.py":  "",
+		"py/helpers.py":         "",
+		"py/db/__init__.py":     "",
+		"py/db/session.py":      "",
+	})
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"handler.go", []string{"store/cache.go", "store/store.go"}},
+		{"web/app.ts", []string{"web/c.js", "web/lib.ts", "web/util.ts", "web/widgets/index.tsx"}},
+		{"py/app.py", []string{"py/db/__init__.py", "py/db/session.py", "py/helpers.py", "py/models.py", "py/views/__init__.py"}},
+		{"store/store.go", nil},
+	}
+
+	r := NewResolver(root)
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, err := r.Imports(filepath.FromSlash(tt.file))
+			if err != nil {
+				t.Fatalf("Imports() error = %v", err)
+			}
+			for i := range got {
+				got[i] = filepath.ToSlash(got[i])
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Imports(%q) = %v; want %v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolverReachable(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"a.js": "import './b.js';",
+		"b.js": "import './c.js';",
+		"c.js": "import './a.js';",
+		"d.js": "",
+	})
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"a.js"}},
+		{1, []string{"a.js", "b.js"}},
+		{-1, []string{"a.js", "b.js", "c.js"}},
+	}
+
+	r := NewResolver(root)
+	for _, tt := range tests {
+		reached, err := r.Reachable([]string{"a.js"}, tt.depth)
+		if err != nil {
+			t.Fatalf("Reachable() error = %v", err)
+		}
+		var got []string
+		for file := range reached {
+			got = append(got, file)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Reachable(depth %d) = %v; want %v", tt.depth, got, tt.want)
+		}
+	}
+
+	if _, err := r.Reachable([]string{"missing.js"}, -1); err == nil {
+		t.Error("Reachable() of a missing file should fail")
+	}
+}
@@ -0,0 +1,179 @@
+package language
+
+// definition rows sharing a name form one language whose extensions use
+// different fence languages or defaults, e.g. TypeScript with .ts, .tsx and
+// the declaration files .d.ts. Compound extensions such as .d.ts take
+// precedence over the last extension of a file name.
+type definition struct {
+	name       string
+	fence      string
+	extensions []string
+	enabled    bool
+}
+
+var builtinLanguages = []definition{
+	{"abap", "abap", []string{".abap"}, false},
+	{"ada", "ada", []string{".adb", ".ads"}, false},
+	{"apex", "apex", []string{".cls", ".trigger"}, false},
+	{"applescript", "applescript", []string{".applescript"}, false},
+	{"asciidoc", "asciidoc", []string{".adoc", ".asciidoc"}, false},
+	{"assembly", "asm", []string{".asm", ".s", ".S"}, false},
+	{"astro", "astro", []string{".astro"}, false},
+	{"autohotkey", "autohotkey", []string{".ahk"}, false},
+	{"awk", "awk", []string{".awk"}, false},
+	{"batch", "bat", []string{".bat", ".cmd"}, false},
+	{"bibtex", "bibtex", []string{".bib"}, false},
+	{"bicep", "bicep", []string{".bicep"}, false},
+	{"c", "c", []string{".c", ".h"}, false},
+	{"clojure", "clojure", []string{".clj", ".cljs", ".cljc", ".edn"}, false},
+	{"cmake", "cmake", []string{".cmake"}, false},
+	{"cobol", "cobol", []string{".cob", ".cbl", ".cpy"}, false},
+	{"coffeescript", "coffeescript", []string{".coffee"}, false},
+	{"cpp", "cpp", []string{".cpp", ".cc", ".cxx", ".c++", ".hpp", ".hh", ".hxx", ".h++", ".ipp", ".tpp"}, false},
+	{"crystal", "crystal", []string{".cr"}, false},
+	{"csharp", "csharp", []string{".cs", ".csx"}, false},
+	{"css", "css", []string{".css"}, false},
+	{"css", "css", []string{".min.css"}, false},
+	{"csv", "csv", []string{".csv"}, false},
+	{"cuda", "cuda", []string{".cu", ".cuh"}, false},
+	{"cue", "cue", []string{".cue"}, false},
+	{"d", "d", []string{".d"}, false},
+	{"dart", "dart", []string{".dart"}, false},
+	{"dhall", "dhall", []string{".dhall"}, false},
+	{"diff", "diff", []string{".diff", ".patch"}, false},
+	{"dockerfile", "dockerfile", []string{".dockerfile"}, true},
+	{"ejs", "ejs", []string{".ejs"}, false},
+	{"elixir", "elixir", []string{".ex", ".exs"}, false},
+	{"elm", "elm", []string{".elm"}, false},
+	{"erb", "erb", []string{".erb"}, false},
+	{"erlang", "erlang", []string{".erl", ".hrl"}, false},
+	{"fennel", "fennel", []string{".fnl"}, false},
+	{"fish", "fish", []string{".fish"}, false},
+	{"fortran", "fortran", []string{".f", ".f90", ".f95", ".f03", ".for"}, false},
+	{"fsharp", "fsharp", []string{".fs", ".fsi", ".fsx"}, false},
+	{"gdscript", "gdscript", []string{".gd"}, false},
+	{"gleam", "gleam", []string{".gleam"}, false},
+	{"glsl", "glsl", []string{".glsl", ".vert", ".frag", ".geom"}, false},
+	{"go", "go", []string{".go"}, true},
+	{"go", "go", []string{".pb.go"}, false},
+	{"gradle", "groovy", []string{".gradle"}, false},
+	{"graphql", "graphql", []string{".graphql", ".gql"}, false},
+	{"groovy", "groovy", []string{".groovy", ".gvy"}, false},
+	{"haml", "haml", []string{".haml"}, false},
+	{"handlebars", "handlebars", []string{".hbs", ".handlebars"}, false},
+	{"haskell", "haskell", []string{".hs", ".lhs"}, false},
+	{"haxe", "haxe", []string{".hx"}, false},
+	{"hcl", "hcl", []string{".hcl"}, false},
+	{"hlsl", "hlsl", []string{".hlsl"}, false},
+	{"html", "html", []string{".html", ".htm", ".xhtml"}, false},
+	{"http", "http", []string{".http", ".rest"}, false},
+	{"idris", "idris", []string{".idr"}, false},
+	{"ini", "ini", []string{".ini", ".cfg"}, false},
+	{"java", "java", []string{".java"}, true},
+	{"javascript", "js", []string{".js", ".mjs", ".cjs"}, true},
+	{"javascript", "jsx", []string{".jsx"}, true},
+	{"javascript", "js", []string{".min.js"}, false},
+	{"jinja", "jinja", []string{".j2", ".jinja", ".jinja2"}, false},
+	{"json", "json", []string{".json", ".jsonc", ".json5"}, false},
+	{"jsonnet", "jsonnet", []string{".jsonnet", ".libsonnet"}, false},
+	{"julia", "julia", []string{".jl"}, false},
+	{"just", "just", []string{".just"}, false},
+	{"kotlin", "kotlin", []string{".kt", ".kts"}, false},
+	{"latex", "latex", []string{".tex", ".sty"}, false},
+	{"lean", "lean", []string{".lean"}, false},
+	{"less", "less", []string{".less"}, false},
+	{"liquid", "liquid", []string{".liquid"}, false},
+	{"lisp", "lisp", []string{".lisp", ".lsp"}, false},
+	{"lua", "lua", []string{".lua"}, false},
+	{"makefile", "makefile", []string{".mk", ".mak"}, false},
+	{"markdown", "md", []string{".md", ".markdown"}, false},
+	{"mdx", "mdx", []string{".mdx"}, false},
+	{"mermaid", "mermaid", []string{".mmd", ".mermaid"}, false},
+	{"mojo", "mojo", []string{".mojo"}, false},
+	{"nim", "nim", []string{".nim"}, false},
+	{"nix", "nix", []string{".nix"}, false},
+	{"nunjucks", "nunjucks", []string{".njk"}, false},
+	{"nushell", "nu", []string{".nu"}, false},
+	{"objectivec", "objectivec", []string{".m", ".mm"}, false},
+	{"ocaml", "ocaml", []string{".ml", ".mli"}, false},
+	{"odin", "odin", []string{".odin"}, false},
+	{"org", "org", []string{".org"}, false},
+	{"pascal", "pascal", []string{".pas"}, false},
+	{"perl", "perl", []string{".pl", ".pm"}, false},
+	{"php", "php", []string{".php", ".phtml"}, true},
+	{"postcss", "css", []string{".pcss", ".postcss"}, false},
+	{"powershell", "powershell", []string{".ps1", ".psm1", ".psd1"}, false},
+	{"prisma", "prisma", []string{".prisma"}, false},
+	{"properties", "properties", []string{".properties"}, false},
+	{"protobuf", "protobuf", []string{".proto"}, false},
+	{"pug", "pug", []string{".pug", ".jade"}, false},
+	{"puppet", "puppet", []string{".pp"}, false},
+	{"purescript", "purescript", []string{".purs"}, false},
+	{"python", "py", []string{".py", ".pyi", ".pyw"}, true},
+	{"r", "r", []string{".r", ".R"}, false},
+	{"racket", "racket", []string{".rkt"}, false},
+	{"razor", "razor", []string{".cshtml", ".razor"}, false},
+	{"reason", "reason", []string{".re", ".rei"}, false},
+	{"rego", "rego", []string{".rego"}, false},
+	{"restructuredtext", "rst", []string{".rst"}, false},
+	{"ruby", "ruby", []string{".rb", ".rake", ".gemspec", ".ru"}, false},
+	{"rust", "rust", []string{".rs"}, false},
+	{"sass", "sass", []string{".sass"}, false},
+	{"scala", "scala", []string{".scala", ".sc"}, false},
+	{"scheme", "scheme", []string{".scm", ".ss"}, false},
+	{"scss", "scss", []string{".scss"}, false},
+	{"shell", "sh", []string{".sh", ".bash", ".zsh", ".ksh"}, true},
+	{"smalltalk", "smalltalk", []string{".st"}, false},
+	{"sml", "sml", []string{".sml"}, false},
+	{"solidity", "solidity", []string{".sol"}, false},
+	{"sql", "sql", []string{".sql"}, false},
+	{"starlark", "starlark", []string{".bzl", ".star"}, false},
+	{"stylus", "stylus", []string{".styl"}, false},
+	{"svelte", "svelte", []string{".svelte"}, false},
+	{"svg", "xml", []string{".svg"}, false},
+	{"swift", "swift", []string{".swift"}, false},
+	{"tcl", "tcl", []string{".tcl"}, false},
+	{"terraform", "hcl", []string{".tf", ".tfvars"}, false},
+	{"text", "text", []string{".txt", ".text"}, false},
+	{"thrift", "thrift", []string{".thrift"}, false},
+	{"toml", "toml", []string{".toml"}, false},
+	{"tsv", "tsv", []string{".tsv"}, false},
+	{"twig", "twig", []string{".twig"}, false},
+	{"typescript", "ts", []string{".ts", ".mts", ".cts"}, true},
+	{"typescript", "tsx", []string{".tsx"}, true},
+	{"typescript", "ts", []string{".d.ts", ".d.mts", ".d.cts"}, false},
+	{"typst", "typst", []string{".typ"}, false},
+	{"vala", "vala", []string{".vala"}, false},
+	{"vbnet", "vbnet", []string{".vb"}, false},
+	{"verilog", "verilog", []string{".v", ".sv", ".svh"}, false},
+	{"vhdl", "vhdl", []string{".vhd", ".vhdl"}, false},
+	{"vim", "vim", []string{".vim"}, false},
+	{"vue", "vue", []string{".vue"}, false},
+	{"wasm", "wasm", []string{".wat", ".wast"}, false},
+	{"wgsl", "wgsl", []string{".wgsl"}, false},
+	{"xml", "xml", []string{".xml", ".xsd", ".xsl", ".xslt", ".plist", ".csproj"}, false},
+	{"xquery", "xquery", []string{".xq", ".xquery"}, false},
+	{"yaml", "yaml", []string{".yaml", ".yml"}, false},
+	{"zig", "zig", []string{".zig"}, false},
+}
+
+// languageAliases maps alternative names to language names. Aliases never
+// shadow an extension, which --languages resolves first.
+var languageAliases = map[string]string{
+	"c#":              "csharp",
+	"docker":          "dockerfile",
+	"elisp":           "lisp",
+	"f#":              "fsharp",
+	"golang":          "go",
+	"hcl2":            "hcl",
+	"make":            "makefile",
+	"node":            "javascript",
+	"nodejs":          "javascript",
+	"objc":            "objectivec",
+	"objective-c":     "objectivec",
+	"postgres":        "sql",
+	"protocol-buffer": "protobuf",
+	"ps":              "powershell",
+	"py3":             "python",
+	"python3":         "python",
+}
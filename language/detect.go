@@ -0,0 +1,146 @@
+package language
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	detectMaxBytes = 4096
+	detectMaxLines = 5
+)
+
+var interpreterExtensions = map[string]string{
+	"ash":          ".sh",
+	"bash":         ".sh",
+	"bun":          ".js",
+	"cperl":        ".pl",
+	"dash":         ".sh",
+	"deno":         ".ts",
+	"escript":      ".erl",
+	"gawk":         ".awk",
+	"guile":        ".scm",
+	"js2":          ".js",
+	"ksh":          ".sh",
+	"luajit":       ".lua",
+	"mawk":         ".awk",
+	"make":         ".mk",
+	"node":         ".js",
+	"nodejs":       ".js",
+	"osascript":    ".applescript",
+	"pwsh":         ".ps1",
+	"pypy":         ".py",
+	"rscript":      ".r",
+	"runghc":       ".hs",
+	"runhaskell":   ".hs",
+	"sbcl":         ".lisp",
+	"shell-script": ".sh",
+	"tclsh":        ".tcl",
+	"ts-node":      ".ts",
+	"wish":         ".tcl",
+	"zsh":          ".sh",
+}
+
+var (
+	vimModeline   = regexp.MustCompile(`(?:^|\s)(?:vi|vim|ex)(?:[<=>]?\d+)?:.*?\b(?:ft|filetype|syntax|syn)=([A-Za-z0-9_+-]+)`)
+	emacsModeline = regexp.MustCompile(`-\*-(.*?)-\*-`)
+	emacsMode     = regexp.MustCompile(`(?i)(?:^|;)\s*mode:\s*([A-Za-z0-9_+-]+)`)
+)
+
+// DetectFile reads the beginning of a file and returns the extension of the
+// language announced by a shebang or a Vim/Emacs modeline, or "".
+func DetectFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, detectMaxBytes)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return DetectFromContent(head[:n]), nil
+}
+
+func DetectFromContent(head []byte) string {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return ""
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(head))
+	for i := 0; i < detectMaxLines && scanner.Scan(); i++ {
+		line := scanner.Text()
+		if i == 0 && strings.HasPrefix(line, "#!") {
+			if ext := extensionForName(shebangInterpreter(line)); ext != "" {
+				return ext
+			}
+			continue
+		}
+		if ext := extensionForName(modelineMode(line)); ext != "" {
+			return ext
+		}
+	}
+	return ""
+}
+
+func shebangInterpreter(line string) string {
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter != "env" {
+		return interpreter
+	}
+	for _, field := range fields[1:] {
+		if strings.HasPrefix(field, "-") || strings.Contains(field, "=") {
+			continue
+		}
+		return filepath.Base(field)
+	}
+	return ""
+}
+
+func modelineMode(line string) string {
+	if m := vimModeline.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+	m := emacsModeline.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	inner := strings.TrimSpace(m[1])
+	if !strings.Contains(inner, ":") {
+		return inner
+	}
+	if mode := emacsMode.FindStringSubmatch(inner); mode != nil {
+		return mode[1]
+	}
+	return ""
+}
+
+func extensionForName(name string) string {
+	name = strings.ToLower(name)
+	if name == "" {
+		return ""
+	}
+	if ext, exists := interpreterExtensions[name]; exists {
+		return ext
+	}
+	if ext, exists := nameExtensions[name]; exists {
+		return ext
+	}
+	trimmed := strings.TrimRight(name, "0123456789.")
+	if trimmed != name {
+		return extensionForName(trimmed)
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package language
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectFromContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"env python3", "#!/usr/bin/env python3\nprint(1)\n", ".py"},
+		{"python with version", "#!/usr/bin/python3.11\n", ".py"},
+		{"bash", "#!/bin/bash\necho hi\n", ".sh"},
+		{"bash with options", "#!/bin/bash -eu\n", ".sh"},
+		{"sh", "#!/bin/sh\n", ".sh"},
+		{"env with split string", "#!/usr/bin/env -S deno run --allow-net\n", ".ts"},
+		{"env with variable", "#!/usr/bin/env NODE_ENV=production node\n", ".js"},
+		{"ruby", "#!/usr/bin/ruby -w\n", ".rb"},
+		{"perl", "#!/usr/bin/perl\n", ".pl"},
+		{"php", "#!/usr/bin/env php\n<?php\n", ".php"},
+		{"vim modeline", "# vim: set ft=python:\n", ".py"},
+		{"vim modeline filetype", "// vim: filetype=javascript\n", ".js"},
+		{"shebang wins over modeline", "#!/bin/sh\n# vim: ft=python\n", ".sh"},
+		{"unknown shebang falls back to modeline", "#!/opt/custom/runner\n# vim: ft=ruby\n", ".rb"},
+		{"emacs mode", "# -*- mode: ruby; coding: utf-8 -*-\n", ".rb"},
+		{"emacs short form", "# -*- python -*-\n", ".py"},
+		{"emacs coding only", "# -*- coding: utf-8 -*-\n", ""},
+		{"modeline on later line", "#\n#\n# vim: ft=sh\n", ".sh"},
+		{"modeline too late", "1\n2\n3\n4\n5\n# vim: ft=sh\n", ""},
+		{"no shebang", "print(1)\n", ""},
+		{"shebang not on first line", "\n#!/bin/bash\n", ""},
+		{"unknown interpreter", "#!/usr/bin/unknown-thing\n", ""},
+		{"binary content", "#!/bin/sh\x00\x01", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFromContent([]byte(tt.content)); got != tt.want {
+				t.Errorf("DetectFromContent(%q) = %q; want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectFile(t *testing.T) {
+	t.Run("reads shebang from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "script")
+		os.WriteFile(path, []byte("#!/usr/bin/env python3\n"), 0755)
+
+		ext, err := DetectFile(path)
+		if err != nil {
+			t.Fatalf("DetectFile() error: %v", err)
+		}
+		if ext != ".py" {
+			t.Errorf("DetectFile() = %q; want .py", ext)
+		}
+	})
+
+	t.Run("errors for missing file", func(t *testing.T) {
+		if _, err := DetectFile("/nonexistent/script"); err == nil {
+			t.Error("DetectFile() should error for missing file")
+		}
+	})
+}
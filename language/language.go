@@ -4,97 +4,302 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
-var supportedLanguages = map[string]bool{
-	".php":        true,
-	".go":         true,
-	".js":         true,
-	".ts":         true,
-	".py":         true,
-	".sh":         true,
-	".java":       true,
-	".dockerfile": true,
-	".md":         false,
-	".html":       false,
-	".scss":       false,
-	".css":        false,
-	".json":       false,
-	".yaml":       false,
-	".yml":        false,
-	".xml":        false,
-}
-
-var specialFileLanguages = map[string]string{
-	"go.mod": "go",
+type Mapping struct {
+	Pattern string
+	Fence   string
+}
+
+type CustomMappings struct {
+	Extensions []Mapping
+	FileNames  []Mapping
+	Globs      []Mapping
+}
+
+var (
+	supportedLanguages map[string]bool
+	extensionFences    map[string]string
+	nameExtensions     map[string]string
+	languageExtensions map[string][]string
+	customFileNames    map[string]string
+	customGlobs        []Mapping
+)
+
+func init() {
+	resetRegistry()
+}
+
+func resetRegistry() {
+	supportedLanguages = make(map[string]bool)
+	extensionFences = make(map[string]string)
+	nameExtensions = make(map[string]string)
+	languageExtensions = make(map[string][]string)
+	customFileNames = make(map[string]string)
+	customGlobs = nil
+
+	for _, def := range builtinLanguages {
+		for _, ext := range def.extensions {
+			supportedLanguages[ext] = def.enabled
+			extensionFences[ext] = def.fence
+		}
+		if _, exists := nameExtensions[def.name]; !exists {
+			nameExtensions[def.name] = def.extensions[0]
+		}
+		languageExtensions[def.name] = append(languageExtensions[def.name], def.extensions...)
+	}
+	for _, def := range builtinLanguages {
+		if _, exists := nameExtensions[def.fence]; !exists {
+			nameExtensions[def.fence] = def.extensions[0]
+		}
+	}
+}
+
+// SetCustomMappings replaces previously registered custom mappings. New
+// extensions are enabled by default, known extensions only get a new fence
+// language. A fence language that is not a known language name becomes one,
+// so it can be selected by name in --languages. File names and globs are
+// matched against the base name and are always allowed.
+func SetCustomMappings(custom CustomMappings) error {
+	for _, m := range custom.Extensions {
+		if !strings.HasPrefix(m.Pattern, ".") || strings.ContainsAny(m.Pattern, "/*?[") {
+			return fmt.Errorf("invalid extension %q: must start with a dot", m.Pattern)
+		}
+	}
+	for _, m := range custom.Globs {
+		if _, err := filepath.Match(m.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", m.Pattern, err)
+		}
+	}
+	for _, m := range append(append(append([]Mapping{}, custom.Extensions...), custom.FileNames...), custom.Globs...) {
+		if m.Fence == "" {
+			return fmt.Errorf("missing fence language for %q", m.Pattern)
+		}
+	}
+
+	resetRegistry()
+	for _, m := range custom.Extensions {
+		if _, known := supportedLanguages[m.Pattern]; !known {
+			supportedLanguages[m.Pattern] = true
+			if _, named := languageExtensions[m.Fence]; !named || isCustomLanguage(m.Fence) {
+				languageExtensions[m.Fence] = append(languageExtensions[m.Fence], m.Pattern)
+			}
+		}
+		extensionFences[m.Pattern] = m.Fence
+	}
+	for _, m := range custom.FileNames {
+		customFileNames[m.Pattern] = m.Fence
+	}
+	customGlobs = append(customGlobs, custom.Globs...)
+	return nil
+}
+
+func isCustomLanguage(name string) bool {
+	for _, def := range builtinLanguages {
+		if def.name == name {
+			return false
+		}
+	}
+	return true
+}
+
+func customFence(filename string) string {
+	if fence, exists := customFileNames[filename]; exists {
+		return fence
+	}
+	for _, m := range customGlobs {
+		if matched, _ := filepath.Match(m.Pattern, filename); matched {
+			return m.Fence
+		}
+	}
+	return ""
 }
 
 func isDockerfile(filename string) bool {
-	lower := strings.ToLower(filename)
-	return lower == "dockerfile" || strings.HasPrefix(lower, "dockerfile.")
+	f, found := findWellKnownFile(filename)
+	return found && f.fence == "dockerfile"
 }
 
+// ParseLanguages computes the enabled extensions from a comma-separated list
+// of extensions, language names and aliases. Plain tokens replace the
+// defaults, a list starting with +token or -token modifies them. "all" enables
+// every known extension and "defaults" the default ones.
 func ParseLanguages(languages string) map[string]bool {
 	result := make(map[string]bool)
+	for lang, defaultEnabled := range supportedLanguages {
+		result[lang] = defaultEnabled
+	}
 
 	if languages == "" {
-		for lang, defaultEnabled := range supportedLanguages {
-			result[lang] = defaultEnabled
-		}
 		return result
 	}
 
-	for lang := range supportedLanguages {
-		result[lang] = false
+	selectedLanguages := strings.Split(languages, ",")
+	if !isRelativeToken(selectedLanguages[0]) {
+		for lang := range result {
+			result[lang] = false
+		}
 	}
 
-	selectedLanguages := strings.Split(languages, ",")
 	for _, lang := range selectedLanguages {
 		lang = strings.TrimSpace(lang)
-		if !strings.HasPrefix(lang, ".") {
-			lang = "." + lang
+		enable := true
+		switch {
+		case strings.EqualFold(lang, "all"):
+			for ext := range result {
+				result[ext] = true
+			}
+			continue
+		case strings.EqualFold(lang, "defaults"):
+			for ext, defaultEnabled := range supportedLanguages {
+				result[ext] = result[ext] || defaultEnabled
+			}
+			continue
+		case strings.HasPrefix(lang, "+"):
+			lang = lang[1:]
+		case strings.HasPrefix(lang, "-"):
+			lang = lang[1:]
+			enable = false
 		}
-		lang = strings.ToLower(lang)
 
-		if _, exists := supportedLanguages[lang]; exists {
-			result[lang] = true
-		} else {
-			fmt.Fprintf(os.Stderr, "Warning: unrecognized language %q, skipping\n", strings.TrimPrefix(lang, "."))
+		extensions := resolveLanguage(lang, enable)
+		if extensions == nil {
+			fmt.Fprintf(os.Stderr, "Warning: unrecognized language %q, skipping\n", strings.TrimPrefix(strings.ToLower(lang), "."))
+			continue
+		}
+		for _, ext := range extensions {
+			result[ext] = enable
 		}
 	}
 
 	return result
 }
 
-func GetAllowedFileNames(allowedLanguages map[string]bool) map[string]bool {
-	allowedFileNames := make(map[string]bool)
-	if allowedLanguages[".go"] {
-		allowedFileNames["go.mod"] = true
+func isRelativeToken(token string) bool {
+	token = strings.TrimSpace(token)
+	return strings.HasPrefix(token, "+") || strings.HasPrefix(token, "-")
+}
+
+// withCompoundExtensions adds the compound extensions that are enabled by
+// default, such as a custom .blade.php, to the extensions they end with.
+func withCompoundExtensions(extensions []string) []string {
+	var compounds []string
+	for _, ext := range extensions {
+		if isCompound(ext) {
+			continue
+		}
+		for compound, defaultEnabled := range supportedLanguages {
+			if defaultEnabled && isCompound(compound) && filepath.Ext(compound) == ext {
+				compounds = append(compounds, compound)
+			}
+		}
 	}
-	if allowedLanguages[".php"] {
-		allowedFileNames["composer.json"] = true
+	return append(extensions, compounds...)
+}
+
+func isCompound(ext string) bool {
+	return strings.Count(ext, ".") > 1
+}
+
+// resolveLanguage returns the extensions selected by a --languages token. An
+// extension selects only itself. A language name or alias enables the
+// extensions of that language that are not compound, such as .go but not
+// .pb.go, and the compound ones that are on by default; it disables all of
+// them.
+func resolveLanguage(token string, enable bool) []string {
+	token = strings.ToLower(strings.TrimSpace(token))
+	ext := token
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
 	}
-	if allowedLanguages[".js"] || allowedLanguages[".ts"] {
-		allowedFileNames["package.json"] = true
+	if _, exists := supportedLanguages[ext]; exists {
+		return withCompoundExtensions([]string{ext})
 	}
-	if allowedLanguages[".ts"] {
-		allowedFileNames["tsconfig.json"] = true
+
+	name := strings.TrimPrefix(token, ".")
+	if canonical, isAlias := languageAliases[name]; isAlias {
+		name = canonical
 	}
-	if allowedLanguages[".java"] {
-		allowedFileNames["pom.xml"] = true
+	if extensions, exists := languageExtensions[name]; exists {
+		if !enable {
+			return withCompoundExtensions(append([]string{}, extensions...))
+		}
+		var selected []string
+		for _, ext := range extensions {
+			if !isCompound(ext) || supportedLanguages[ext] {
+				selected = append(selected, ext)
+			}
+		}
+		return withCompoundExtensions(selected)
+	}
+	return nil
+}
+
+func GetAllowedFileNames(allowedLanguages map[string]bool) map[string]bool {
+	allowedFileNames := make(map[string]bool)
+	for _, f := range wellKnownFiles {
+		if f.isExact() && f.isEnabled(allowedLanguages) {
+			allowedFileNames[f.pattern] = true
+		}
 	}
 	return allowedFileNames
 }
 
 func GetMarkdownLanguage(filename string, allowedFileNames map[string]bool) string {
-	if isDockerfile(filename) {
-		return "dockerfile"
+	if fence := customFence(filename); fence != "" {
+		return fence
+	}
+	if f, found := findWellKnownFile(filename); found && (!f.isExact() || allowedFileNames[filename]) {
+		return f.fence
+	}
+	return GetExtensionLanguage(FileExtension(filename))
+}
+
+// FileExtension returns the longest registered compound extension of
+// filename, e.g. ".d.ts" for "index.d.ts", and filepath.Ext otherwise.
+func FileExtension(filename string) string {
+	for i := 1; i < len(filename); i++ {
+		if filename[i] != '.' {
+			continue
+		}
+		suffix := filename[i:]
+		if !isCompound(suffix) {
+			break
+		}
+		if _, exists := supportedLanguages[suffix]; exists {
+			return suffix
+		}
+	}
+	return filepath.Ext(filename)
+}
+
+// FenceForLanguage maps a language name such as the value of a
+// linguist-language attribute to its fence language, e.g. "Shell" to "sh".
+// Unknown names are used as fence language in lower case.
+func FenceForLanguage(name string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(name), "-"))
+	if canonical, isAlias := languageAliases[key]; isAlias {
+		key = canonical
 	}
-	if lang, exists := specialFileLanguages[filename]; exists && allowedFileNames[filename] {
-		return lang
+	if extensions, exists := languageExtensions[key]; exists {
+		return extensionFences[extensions[0]]
+	}
+	if ext, exists := nameExtensions[key]; exists {
+		return extensionFences[ext]
+	}
+	if fence, exists := extensionFences["."+key]; exists {
+		return fence
+	}
+	return key
+}
+
+func GetExtensionLanguage(ext string) string {
+	if fence, exists := extensionFences[ext]; exists {
+		return fence
 	}
-	lang := strings.TrimPrefix(filepath.Ext(filename), ".")
+	lang := strings.TrimPrefix(ext, ".")
 	if lang == "" {
 		return "plaintext"
 	}
@@ -102,10 +307,43 @@ func GetMarkdownLanguage(filename string, allowedFileNames map[string]bool) stri
 }
 
 func IsFileAllowed(filename string, allowedLanguages, allowedFileNames map[string]bool) bool {
-	if allowedLanguages[".dockerfile"] && isDockerfile(filename) {
+	if f, found := findWellKnownFile(filename); found && !f.isExact() && f.isEnabled(allowedLanguages) {
 		return true
 	}
-	return allowedFileNames[filename] || allowedLanguages[filepath.Ext(filename)]
+	if customFence(filename) != "" {
+		return true
+	}
+	return allowedFileNames[filename] || allowedLanguages[FileExtension(filename)]
+}
+
+type Language struct {
+	Name       string
+	Aliases    []string
+	Extensions []string
+	Defaults   []string
+}
+
+// Languages lists all registered languages sorted by name. Defaults holds the
+// extensions enabled when --languages is not given.
+func Languages() []Language {
+	aliases := make(map[string][]string)
+	for alias, name := range languageAliases {
+		aliases[name] = append(aliases[name], alias)
+	}
+
+	var languages []Language
+	for name, extensions := range languageExtensions {
+		lang := Language{Name: name, Aliases: aliases[name], Extensions: extensions}
+		sort.Strings(lang.Aliases)
+		for _, ext := range extensions {
+			if supportedLanguages[ext] {
+				lang.Defaults = append(lang.Defaults, ext)
+			}
+		}
+		languages = append(languages, lang)
+	}
+	sort.Slice(languages, func(i, j int) bool { return languages[i].Name < languages[j].Name })
+	return languages
 }
 
 func GetActiveLanguages(allowedLanguages map[string]bool) []string {
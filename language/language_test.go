@@ -17,6 +17,17 @@ func langMap(enabled ...string) map[string]bool {
 	return m
 }
 
+func withLanguages(base map[string]bool, enabled bool, exts ...string) map[string]bool {
+	m := make(map[string]bool)
+	for k, v := range base {
+		m[k] = v
+	}
+	for _, e := range exts {
+		m[e] = enabled
+	}
+	return m
+}
+
 func TestIsDockerfile(t *testing.T) {
 	tests := []struct {
 		filename string
@@ -176,6 +187,10 @@ func TestParseLanguages(t *testing.T) {
 	for k, v := range supportedLanguages {
 		defaultsExpected[k] = v
 	}
+	allExpected := make(map[string]bool)
+	for k := range supportedLanguages {
+		allExpected[k] = true
+	}
 
 	tests := []struct {
 		name     string
@@ -188,9 +203,22 @@ func TestParseLanguages(t *testing.T) {
 		{"languages without dots", "go,js", langMap(".go", ".js")},
 		{"uppercase languages", "GO,JS", langMap(".go", ".js")},
 		{"languages with spaces", " go , js ", langMap(".go", ".js")},
-		{"unsupported language ignored", "go,ruby,js", langMap(".go", ".js")},
+		{"unsupported language ignored", "go,klingon,js", langMap(".go", ".js")},
+		{"language name", "python", langMap(".py", ".pyi", ".pyw")},
+		{"language name expands to all fences", "typescript", langMap(".ts", ".mts", ".cts", ".tsx")},
+		{"compound extension", "d.ts", langMap(".d.ts")},
+		{"alias", "golang,Shell", langMap(".go", ".sh", ".bash", ".zsh", ".ksh")},
+		{"extension takes precedence over name", "ts", langMap(".ts")},
 		{"dockerfile explicitly enabled", "dockerfile", langMap(".dockerfile")},
 		{"dockerfile uppercase", "DOCKERFILE", langMap(".dockerfile")},
+		{"added to defaults", "+rs,+kotlin", withLanguages(defaultsExpected, true, ".rs", ".kt", ".kts")},
+		{"removed from defaults", "-shell", withLanguages(defaultsExpected, false, ".sh", ".bash", ".zsh", ".ksh")},
+		{"added and removed", " -sh , +yml", withLanguages(withLanguages(defaultsExpected, false, ".sh"), true, ".yml")},
+		{"plain token after relative token adds", "-sh,rs", withLanguages(withLanguages(defaultsExpected, false, ".sh"), true, ".rs")},
+		{"all except json", "all,-json", withLanguages(allExpected, false, ".json")},
+		{"defaults plus language", "defaults,rust", withLanguages(defaultsExpected, true, ".rs")},
+		{"language plus defaults", "rs,defaults", withLanguages(defaultsExpected, true, ".rs")},
+		{"unsupported relative token ignored", "+klingon", defaultsExpected},
 	}
 
 	for _, tt := range tests {
@@ -361,3 +389,244 @@ func TestGetInactiveLanguages(t *testing.T) {
 	})
 }
 
+func TestBuiltinLanguages(t *testing.T) {
+	seen := make(map[string]string)
+	for _, def := range builtinLanguages {
+		if def.name == "" || def.fence == "" || len(def.extensions) == 0 {
+			t.Errorf("incomplete definition %+v", def)
+		}
+		for _, ext := range def.extensions {
+			if other, exists := seen[ext]; exists {
+				t.Errorf("extension %s defined by %s and %s", ext, other, def.name)
+			}
+			seen[ext] = def.name
+		}
+	}
+
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"main.kt", "kotlin"},
+		{"lib.rs", "rust"},
+		{"main.tf", "hcl"},
+		{"schema.sql", "sql"},
+		{"api.proto", "protobuf"},
+		{"App.vue", "vue"},
+		{"config.yml", "yaml"},
+		{"notes.markdown", "md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := GetMarkdownLanguage(tt.filename, map[string]bool{}); got != tt.want {
+				t.Errorf("GetMarkdownLanguage(%q) = %v; want %v", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetCustomMappings(t *testing.T) {
+	defer resetRegistry()
+
+	err := SetCustomMappings(CustomMappings{
+		Extensions: []Mapping{{".foo", "foolang"}, {".h", "cpp"}},
+		FileNames:  []Mapping{{"Jenkinsfile", "groovy"}},
+		Globs:      []Mapping{{"*.conf.tmpl", "nginx"}},
+	})
+	if err != nil {
+		t.Fatalf("SetCustomMappings() error: %v", err)
+	}
+
+	languages := ParseLanguages("")
+	if !languages[".foo"] {
+		t.Error("new custom extension should be enabled by default")
+	}
+	if languages[".h"] {
+		t.Error("remapping a known extension should keep its default state")
+	}
+
+	tests := []struct {
+		filename    string
+		wantFence   string
+		wantAllowed bool
+	}{
+		{"main.foo", "foolang", true},
+		{"util.h", "cpp", false},
+		{"Jenkinsfile", "groovy", true},
+		{"site.conf.tmpl", "nginx", true},
+		{"other.tmpl", "tmpl", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := GetMarkdownLanguage(tt.filename, map[string]bool{}); got != tt.wantFence {
+				t.Errorf("GetMarkdownLanguage(%q) = %v; want %v", tt.filename, got, tt.wantFence)
+			}
+			if got := IsFileAllowed(tt.filename, languages, map[string]bool{}); got != tt.wantAllowed {
+				t.Errorf("IsFileAllowed(%q) = %v; want %v", tt.filename, got, tt.wantAllowed)
+			}
+		})
+	}
+
+	t.Run("replaces previous mappings", func(t *testing.T) {
+		if err := SetCustomMappings(CustomMappings{}); err != nil {
+			t.Fatalf("SetCustomMappings() error: %v", err)
+		}
+		if _, exists := supportedLanguages[".foo"]; exists {
+			t.Error(".foo should be removed after resetting mappings")
+		}
+		if GetMarkdownLanguage("Jenkinsfile", map[string]bool{}) != "plaintext" {
+			t.Error("Jenkinsfile mapping should be removed after resetting mappings")
+		}
+	})
+
+	t.Run("rejects invalid mappings", func(t *testing.T) {
+		invalid := []CustomMappings{
+			{Extensions: []Mapping{{"foo", "foolang"}}},
+			{Extensions: []Mapping{{".foo", ""}}},
+			{Globs: []Mapping{{"[", "x"}}},
+		}
+		for _, custom := range invalid {
+			if err := SetCustomMappings(custom); err == nil {
+				t.Errorf("SetCustomMappings(%+v) should error", custom)
+			}
+		}
+	})
+}
+
+func TestLanguages(t *testing.T) {
+	defer resetRegistry()
+	if err := SetCustomMappings(CustomMappings{Extensions: []Mapping{{".foo", "foolang"}, {".bar", "foolang"}}}); err != nil {
+		t.Fatalf("SetCustomMappings() error: %v", err)
+	}
+
+	byName := make(map[string]Language)
+	var names []string
+	for _, lang := range Languages() {
+		byName[lang.Name] = lang
+		names = append(names, lang.Name)
+	}
+	if !sort.StringsAreSorted(names) {
+		t.Error("Languages() should be sorted by name")
+	}
+
+	tests := []struct {
+		name string
+		want Language
+	}{
+		{"go", Language{"go", []string{"golang"}, []string{".go", ".pb.go"}, []string{".go"}}},
+		{"typescript", Language{"typescript", nil, []string{".ts", ".mts", ".cts", ".tsx", ".d.ts", ".d.mts", ".d.cts"}, []string{".ts", ".mts", ".cts", ".tsx"}}},
+		{"kotlin", Language{"kotlin", nil, []string{".kt", ".kts"}, nil}},
+		{"foolang", Language{"foolang", nil, []string{".foo", ".bar"}, []string{".foo", ".bar"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := byName[tt.name]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Languages()[%q] = %+v; want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("custom language selectable by name", func(t *testing.T) {
+		got := ParseLanguages("foolang")
+		if !got[".foo"] || !got[".bar"] || got[".go"] {
+			t.Errorf("ParseLanguages(foolang) enabled %v", GetActiveLanguages(got))
+		}
+	})
+}
+
+func TestFileExtension(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"main.go", ".go"},
+		{"api.pb.go", ".pb.go"},
+		{"index.d.ts", ".d.ts"},
+		{"index.d.mts", ".d.mts"},
+		{"app.test.ts", ".ts"},
+		{"archive.tar.gz", ".gz"},
+		{"jquery.min.js", ".min.js"},
+		{".bashrc", ".bashrc"},
+		{".d.ts", ".ts"},
+		{"Makefile", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := FileExtension(tt.filename); got != tt.want {
+				t.Errorf("FileExtension(%q) = %q; want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompoundExtensions(t *testing.T) {
+	defer resetRegistry()
+	if err := SetCustomMappings(CustomMappings{Extensions: []Mapping{{".blade.php", "blade"}, {".spec.ts", "ts"}}}); err != nil {
+		t.Fatalf("SetCustomMappings() error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		languages   string
+		filename    string
+		wantAllowed bool
+		wantFence   string
+	}{
+		{"generated go excluded by default", "", "api.pb.go", false, "go"},
+		{"regular go included by default", "", "api.go", true, "go"},
+		{"declarations excluded by default", "", "index.d.ts", false, "ts"},
+		{"declarations enabled explicitly", "ts,d.ts", "index.d.ts", true, "ts"},
+		{"generated go excluded with go", "go", "api.pb.go", false, "go"},
+		{"generated go excluded with golang", "golang", "api.pb.go", false, "go"},
+		{"declarations excluded with typescript", "typescript", "index.d.ts", false, "ts"},
+		{"declarations removed with typescript", "all,-typescript", "index.d.ts", false, "ts"},
+		{"custom compound follows its language", "blade", "view.blade.php", true, "blade"},
+		{"custom compound follows its extension", "php", "view.blade.php", true, "blade"},
+		{"custom compound off without its extension", "go", "view.blade.php", false, "blade"},
+		{"custom compound selected alone", "spec.ts", "app.spec.ts", true, "ts"},
+		{"last extension does not select compound", "spec.ts", "app.ts", false, "ts"},
+		{"custom compound removed with its extension", "-php", "view.blade.php", false, "blade"},
+		{"custom compound removed alone", "-blade.php", "view.blade.php", false, "blade"},
+		{"extension kept when compound removed", "-blade.php", "index.php", true, "php"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed := ParseLanguages(tt.languages)
+			if got := IsFileAllowed(tt.filename, allowed, map[string]bool{}); got != tt.wantAllowed {
+				t.Errorf("IsFileAllowed(%q) with %q = %v; want %v", tt.filename, tt.languages, got, tt.wantAllowed)
+			}
+			if got := GetMarkdownLanguage(tt.filename, map[string]bool{}); got != tt.wantFence {
+				t.Errorf("GetMarkdownLanguage(%q) = %q; want %q", tt.filename, got, tt.wantFence)
+			}
+		})
+	}
+}
+
+func TestFenceForLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Shell", "sh"},
+		{"Python", "py"},
+		{"TypeScript", "ts"},
+		{"golang", "go"},
+		{"Protocol Buffer", "protobuf"},
+		{"C++", "cpp"},
+		{"tsx", "tsx"},
+		{"Brainfuck", "brainfuck"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FenceForLanguage(tt.name); got != tt.want {
+				t.Errorf("FenceForLanguage(%q) = %q; want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
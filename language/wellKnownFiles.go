@@ -0,0 +1,95 @@
+package language
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+type wellKnownFile struct {
+	pattern    string
+	ignoreCase bool
+	fence      string
+	enabledBy  []string
+}
+
+var wellKnownFiles = []wellKnownFile{
+	{"go.mod", false, "go", []string{".go"}},
+	{"composer.json", false, "json", []string{".php"}},
+	{"package.json", false, "json", []string{".js", ".ts"}},
+	{"tsconfig.json", false, "json", []string{".ts"}},
+	{"pom.xml", false, "xml", []string{".java"}},
+	{"pyproject.toml", false, "toml", []string{".py"}},
+	{"requirements.txt", false, "text", []string{".py"}},
+	{"Pipfile", false, "toml", []string{".py"}},
+	{"Cargo.toml", false, "toml", []string{".rs"}},
+	{"Gemfile", false, "ruby", []string{".rb"}},
+	{"Rakefile", false, "ruby", []string{".rb"}},
+	{"Guardfile", false, "ruby", []string{".rb"}},
+	{"Podfile", false, "ruby", []string{".rb"}},
+	{"Vagrantfile", false, "ruby", []string{".rb"}},
+	{"Brewfile", false, "ruby", []string{".rb"}},
+	{"Fastfile", false, "ruby", []string{".rb"}},
+	{"Makefile", false, "makefile", []string{".mk"}},
+	{"makefile", false, "makefile", []string{".mk"}},
+	{"GNUmakefile", false, "makefile", []string{".mk"}},
+	{"CMakeLists.txt", false, "cmake", []string{".cmake"}},
+	{"Jenkinsfile", false, "groovy", []string{".groovy"}},
+	{"Jenkinsfile.*", false, "groovy", []string{".groovy"}},
+	{"BUILD", false, "starlark", []string{".bzl"}},
+	{"BUILD.bazel", false, "starlark", []string{".bzl"}},
+	{"WORKSPACE", false, "starlark", []string{".bzl"}},
+	{"WORKSPACE.bazel", false, "starlark", []string{".bzl"}},
+	{"MODULE.bazel", false, "starlark", []string{".bzl"}},
+	{"Tiltfile", false, "starlark", []string{".bzl"}},
+	{"justfile", true, "just", []string{".just"}},
+	{".justfile", true, "just", []string{".just"}},
+	{".bashrc", false, "sh", []string{".sh"}},
+	{".bash_profile", false, "sh", []string{".sh"}},
+	{".bash_aliases", false, "sh", []string{".sh"}},
+	{".profile", false, "sh", []string{".sh"}},
+	{".zshrc", false, "sh", []string{".sh"}},
+	{".zprofile", false, "sh", []string{".sh"}},
+	{".zshenv", false, "sh", []string{".sh"}},
+	{".kshrc", false, "sh", []string{".sh"}},
+	{"dockerfile", true, "dockerfile", []string{".dockerfile"}},
+	{"dockerfile.*", true, "dockerfile", []string{".dockerfile"}},
+	{"containerfile", true, "dockerfile", []string{".dockerfile"}},
+	{"containerfile.*", true, "dockerfile", []string{".dockerfile"}},
+}
+
+// isExact reports whether the entry names a single file and can therefore be
+// listed in the allowed file names.
+func (f wellKnownFile) isExact() bool {
+	return !f.ignoreCase && !strings.ContainsAny(f.pattern, "*?[")
+}
+
+func (f wellKnownFile) matches(filename string) bool {
+	pattern := f.pattern
+	if f.ignoreCase {
+		pattern = strings.ToLower(pattern)
+		filename = strings.ToLower(filename)
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return filename == pattern
+	}
+	matched, _ := filepath.Match(pattern, filename)
+	return matched
+}
+
+func (f wellKnownFile) isEnabled(allowedLanguages map[string]bool) bool {
+	for _, ext := range f.enabledBy {
+		if allowedLanguages[ext] {
+			return true
+		}
+	}
+	return false
+}
+
+func findWellKnownFile(filename string) (wellKnownFile, bool) {
+	for _, f := range wellKnownFiles {
+		if f.matches(filename) {
+			return f, true
+		}
+	}
+	return wellKnownFile{}, false
+}
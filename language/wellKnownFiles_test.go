@@ -0,0 +1,76 @@
+package language
+
+import "testing"
+
+func TestWellKnownFiles(t *testing.T) {
+	allowedLanguages := map[string]bool{
+		".mk":         true,
+		".rb":         true,
+		".bzl":        true,
+		".sh":         true,
+		".dockerfile": true,
+		".rs":         false,
+		".groovy":     false,
+	}
+	allowedFileNames := GetAllowedFileNames(allowedLanguages)
+
+	tests := []struct {
+		filename    string
+		wantAllowed bool
+		wantFence   string
+	}{
+		{"Makefile", true, "makefile"},
+		{"GNUmakefile", true, "makefile"},
+		{"Gemfile", true, "ruby"},
+		{"Rakefile", true, "ruby"},
+		{"BUILD.bazel", true, "starlark"},
+		{"BUILD", true, "starlark"},
+		{"build", false, "plaintext"},
+		{".bashrc", true, "sh"},
+		{".zshrc", true, "sh"},
+		{"Containerfile", true, "dockerfile"},
+		{"Cargo.toml", false, "toml"},
+		{"Jenkinsfile", false, "plaintext"},
+		{"Jenkinsfile.release", false, "groovy"},
+		{"CMakeLists.txt", false, "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := IsFileAllowed(tt.filename, allowedLanguages, allowedFileNames); got != tt.wantAllowed {
+				t.Errorf("IsFileAllowed(%q) = %v; want %v", tt.filename, got, tt.wantAllowed)
+			}
+			if got := GetMarkdownLanguage(tt.filename, allowedFileNames); got != tt.wantFence {
+				t.Errorf("GetMarkdownLanguage(%q) = %v; want %v", tt.filename, got, tt.wantFence)
+			}
+		})
+	}
+}
+
+func TestGetAllowedFileNamesFromRegistry(t *testing.T) {
+	allowed := GetAllowedFileNames(map[string]bool{".rs": true, ".py": true, ".dockerfile": true})
+
+	for _, name := range []string{"Cargo.toml", "pyproject.toml", "requirements.txt", "Pipfile"} {
+		if !allowed[name] {
+			t.Errorf("expected %s in allowed file names %v", name, allowed)
+		}
+	}
+	for _, name := range []string{"dockerfile", "containerfile", "go.mod"} {
+		if allowed[name] {
+			t.Errorf("did not expect %s in allowed file names %v", name, allowed)
+		}
+	}
+}
+
+func TestWellKnownFilesEnabledByKnownLanguages(t *testing.T) {
+	for _, f := range wellKnownFiles {
+		if len(f.enabledBy) == 0 {
+			t.Errorf("%s is not enabled by any language", f.pattern)
+		}
+		for _, ext := range f.enabledBy {
+			if _, exists := supportedLanguages[ext]; !exists {
+				t.Errorf("%s is enabled by unknown extension %s", f.pattern, ext)
+			}
+		}
+	}
+}
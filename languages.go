@@ -0,0 +1,39 @@
+package main
+
+import (
+	"code2md/c2mConfig"
+	"code2md/language"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func runLanguages(args []string) error {
+	if _, err := c2mConfig.InitializeConfigFromArgs(args); err != nil {
+		return fmt.Errorf("initializing config: %w", err)
+	}
+
+	displayLanguages(language.Languages())
+	return nil
+}
+
+func displayLanguages(languages []language.Language) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tALIASES\tEXTENSIONS\tDEFAULT")
+	for _, lang := range languages {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", lang.Name, strings.Join(lang.Aliases, ", "), strings.Join(lang.Extensions, " "), defaultState(lang))
+	}
+	w.Flush()
+}
+
+func defaultState(lang language.Language) string {
+	switch len(lang.Defaults) {
+	case 0:
+		return "off"
+	case len(lang.Extensions):
+		return "on"
+	default:
+		return strings.Join(lang.Defaults, " ")
+	}
+}
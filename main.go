@@ -9,35 +9,108 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"sort"
+	"strings"
 )
 
 var VersionNumber string
 
+type command struct {
+	name     string
+	summary  string
+	synopsis string
+	flags    func() *c2mConfig.FlagSet
+	run      func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"dump", "Convert the source files of a directory into Markdown (default)", "", c2mConfig.NewDumpFlagSet, runDump},
+		{"apply", "Write the file blocks of an edited dump back onto the source tree", "", newApplyFlagSet, runApply},
+		{"languages", "List supported languages, their aliases and extensions", "languages [dump flags]", nil, runLanguages},
+		{"config", "Show the effective configuration and where each value comes from", "config show [dump flags]", nil, runConfig},
+		{"help", "Show help for a command", "help [command]", nil, runHelp},
+	}
+}
+
 func main() {
-	config, err := c2mConfig.InitializeConfigFromFlags()
+	cmd, args, err := selectCommand(os.Args[1:])
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		displayUsageInstructions(nil, false)
+		os.Exit(1)
+	}
+
+	if err := cmd.run(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+}
+
+func selectCommand(args []string) (command, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return commands[0], args, nil
+	}
+	if cmd, ok := findCommand(args[0]); ok {
+		return cmd, args[1:], nil
+	}
+	return command{}, nil, fmt.Errorf("unknown command %q", args[0])
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func runDump(args []string) error {
+	config, err := c2mConfig.InitializeConfigFromArgs(args)
+	if err != nil {
+		return fmt.Errorf("initializing config: %w", err)
+	}
 
 	if config.Version {
 		displayVersion()
-		return
+		return nil
 	}
 
 	if !c2mConfig.IsConfigValid(config) || config.Help {
 		displayUsageInstructions(config, !config.Help)
-		return
+		return nil
 	}
 
-	if err := run(config); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	return run(config)
+}
+
+func runHelp(args []string) error {
+	if len(args) == 0 {
+		displayUsageInstructions(nil, false)
+		return nil
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	if cmd.flags == nil {
+		fmt.Printf("Usage: code2md %s\n", cmd.synopsis)
+		return nil
 	}
+	displayCommandUsage(cmd.flags())
+	return nil
 }
 
 func run(config *c2mConfig.Config) error {
 	var err error
+	if config.Verbose {
+		displayEnabledLanguages(config)
+	}
+
 	outputWriter := os.Stdout
 
 	if config.OutputMarkdown != "" {
@@ -59,15 +132,41 @@ func run(config *c2mConfig.Config) error {
 		}()
 	}
 
+	stats := &processor.Stats{}
 	err = processor.ProcessDirectory(
 		processor.Options{
-			InputFolder:      config.InputFolder,
-			AllowedLanguages: config.AllowedLanguages,
-			AllowedFileNames: config.AllowedFileNames,
-			IgnorePatterns:   patternMatcher.CompilePatterns(config.IgnorePatterns),
-			MaxFileSize:      config.MaxFileSize,
+			InputFolder:       config.InputFolder,
+			AllowedLanguages:  config.AllowedLanguages,
+			AllowedFileNames:  config.AllowedFileNames,
+			IgnorePatterns:    patternMatcher.CompilePatterns(config.IgnorePatterns),
+			MaxFileSize:       config.MaxFileSize,
+			Generated:         config.Generated,
+			StripComments:     config.StripComments,
+			StripLicense:      config.StripLicense,
+			Outline:           config.Outline,
+			FullPatterns:      patternMatcher.CompilePatterns(config.FullPatterns),
+			Redact:            config.Redact,
+			FailOnSecrets:     config.FailOnSecrets,
+			SensitivePatterns: config.Sensitive,
+			Jobs:              config.Jobs,
+			Order:             config.Order,
+			FirstPatterns:     patternMatcher.CompilePatterns(config.FirstPatterns),
+			LastPatterns:      patternMatcher.CompilePatterns(config.LastPatterns),
+			MaxLines:          config.MaxLines,
+			TailLines:         config.TailLines,
+			MaxBytesPerFile:   config.MaxBytesPerFile,
+			Hidden:            config.Hidden,
+			FollowSymlinks:    config.FollowSymlinks,
+			NoOutsideSymlinks: config.NoOutsideSymlinks,
+			FocusFiles:        config.FocusFiles,
+			FocusDepth:        config.FocusDepth,
+			Verbose:           config.Verbose,
+			Stats:             stats,
 		}, outputWriter,
 	)
+	if config.Stats {
+		stats.Print(os.Stderr)
+	}
 	if err != nil {
 		return fmt.Errorf("processing directory %s: %w", config.InputFolder, err)
 	}
@@ -96,21 +195,34 @@ func displayUsageInstructions(config *c2mConfig.Config, showError bool) {
 	if showError {
 		fmt.Println("Error: You have to provide an input folder.")
 	}
-	fmt.Println("Usage: code2md -i <input_folder> -o <output_markdown> [--languages <languages>] [--ignore <ignore_patterns>]")
-	fmt.Println("| Flag              | Short | Description                                                     |")
-	fmt.Println("| ----------------- | ----- | --------------------------------------------------------------- |")
-	fmt.Println("| `--input`         | `-i`  | Input directory to scan (required)                              |")
-	fmt.Println("| `--output`        | `-o`  | Output Markdown file (optional, defaults to stdout)             |")
-	fmt.Println("| `--languages`     | `-l`  | Comma-separated list of allowed languages (extensions or names) |")
-	fmt.Println("| `--ignore`        | `-I`  | Comma-separated ignore patterns                                 |")
-	fmt.Println("| `--max-file-size` | `-m`  | Maximum file size in bytes (default: 100MB)                     |")
-	fmt.Println("| `--help`          | `-h`  | Show help                                                       |")
-	fmt.Println("| `--version`       | `-v`  | Show version information                                        |")
+
+	dump := c2mConfig.NewDumpFlagSet()
+	fmt.Printf("Usage: %s\n", dump.Synopsis())
+	fmt.Println("       code2md <command> [flags]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	for _, cmd := range commands {
+		fmt.Printf("  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Println()
+	fmt.Println("Flags for dump:")
+	fmt.Print(dump.FlagTable())
 
 	if config != nil {
 		activeLangs := language.GetActiveLanguages(config.AllowedLanguages)
-		inactiveLangs := language.GetInactiveLanguages(config.AllowedLanguages)
+		sort.Strings(activeLangs)
 		fmt.Printf("By default, these languages are activated: %v\n", activeLangs)
-		fmt.Printf("Supported languages that need to be activated manually: %v\n", inactiveLangs)
+		fmt.Println("Run 'code2md languages' to list all supported languages and their aliases.")
 	}
 }
+
+func displayEnabledLanguages(config *c2mConfig.Config) {
+	active := language.GetActiveLanguages(config.AllowedLanguages)
+	sort.Strings(active)
+	fmt.Fprintf(os.Stderr, "Enabled languages (%d): %s\n", len(active), strings.Join(active, ", "))
+}
+
+func displayCommandUsage(fs *c2mConfig.FlagSet) {
+	fmt.Printf("Usage: %s\n", fs.Synopsis())
+	fmt.Print(fs.FlagTable())
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"code2md/c2mConfig"
+	"code2md/language"
 	"os"
 	"path/filepath"
 	"strings"
@@ -160,3 +161,235 @@ func TestDisplayUsageInstructions(t *testing.T) {
 		}
 	})
 }
+
+func TestSelectCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCmd  string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"no arguments defaults to dump", nil, "dump", nil, false},
+		{"flags default to dump", []string{"-i", "."}, "dump", []string{"-i", "."}, false},
+		{"explicit dump", []string{"dump", "-i", "."}, "dump", []string{"-i", "."}, false},
+		{"apply", []string{"apply", "edited.md"}, "apply", []string{"edited.md"}, false},
+		{"unknown command", []string{"unknown"}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args, err := selectCommand(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectCommand() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if cmd.name != tt.wantCmd {
+				t.Errorf("selectCommand() command = %q; want %q", cmd.name, tt.wantCmd)
+			}
+			if strings.Join(args, " ") != strings.Join(tt.wantArgs, " ") {
+				t.Errorf("selectCommand() args = %v; want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRunHelp(t *testing.T) {
+	t.Run("shows flags of a command", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			if err := runHelp([]string{"apply"}); err != nil {
+				t.Errorf("runHelp() error: %v", err)
+			}
+		})
+
+		if !strings.Contains(output, "`--yes`") {
+			t.Errorf("apply help should list --yes, got: %s", output)
+		}
+	})
+
+	t.Run("errors for unknown command", func(t *testing.T) {
+		if err := runHelp([]string{"unknown"}); err == nil {
+			t.Error("runHelp() should error for unknown command")
+		}
+	})
+}
+
+func TestRunConfig(t *testing.T) {
+	t.Run("shows settings with sources", func(t *testing.T) {
+		tempDir := t.TempDir()
+		os.WriteFile(filepath.Join(tempDir, ".code2md.yaml"), []byte("max-file-size: 2048\n"), 0644)
+
+		var runErr error
+		output := captureStdout(t, func() {
+			runErr = runConfig([]string{"show", "-i", tempDir})
+		})
+
+		if runErr != nil {
+			t.Fatalf("runConfig() error: %v", runErr)
+		}
+		if !strings.Contains(output, ".code2md.yaml:1") {
+			t.Errorf("output should name the config file line as source, got: %s", output)
+		}
+		if !strings.Contains(output, "flag --input") {
+			t.Errorf("output should name the flag as source, got: %s", output)
+		}
+	})
+
+	t.Run("requires show", func(t *testing.T) {
+		if err := runConfig(nil); err == nil {
+			t.Error("runConfig() should error without show")
+		}
+	})
+}
+
+func TestRunLanguages(t *testing.T) {
+	t.Run("lists names, aliases, extensions and defaults", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		var runErr error
+		output := captureStdout(t, func() {
+			runErr = runLanguages([]string{"-i", tempDir})
+		})
+
+		if runErr != nil {
+			t.Fatalf("runLanguages() error: %v", runErr)
+		}
+		for _, want := range []string{"LANGUAGE", "golang", ".ts .mts .cts .tsx", "kotlin"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output should contain %q, got: %s", want, output)
+			}
+		}
+	})
+
+	t.Run("includes languages from the config file", func(t *testing.T) {
+		tempDir := t.TempDir()
+		os.WriteFile(filepath.Join(tempDir, ".code2md.yaml"), []byte("language-map:\n  extensions:\n    .foo: foolang\n"), 0644)
+
+		output := captureStdout(t, func() {
+			runLanguages([]string{"-i", tempDir})
+		})
+
+		if !strings.Contains(output, "foolang") {
+			t.Errorf("output should contain the custom language, got: %s", output)
+		}
+	})
+}
+
+func TestDefaultState(t *testing.T) {
+	tests := []struct {
+		name string
+		lang language.Language
+		want string
+	}{
+		{"all enabled", language.Language{Extensions: []string{".go"}, Defaults: []string{".go"}}, "on"},
+		{"none enabled", language.Language{Extensions: []string{".kt"}}, "off"},
+		{"partially enabled", language.Language{Extensions: []string{".ts", ".d.ts"}, Defaults: []string{".ts"}}, ".ts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultState(tt.lang); got != tt.want {
+				t.Errorf("defaultState() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunApplyRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	inputDir := filepath.Join(tempDir, "input")
+	files := map[string]string{
+		"src/b.go":          "package b\n",
+		"vendor/lib/lib.go": "package lib\n",
+		"y.go":              "package y\n",
+		"README.md":         "# Project\n\n# Install\n```sh\nmake\n```\n\nDone.",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(inputDir, path)), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(inputDir, path), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	if err := os.Symlink("src/b.go", filepath.Join(inputDir, "link.go")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	dump := filepath.Join(tempDir, "code.md")
+	config := &c2mConfig.Config{
+		InputFolder:      inputDir,
+		OutputMarkdown:   dump,
+		AllowedLanguages: map[string]bool{".go": true, ".md": true},
+		AllowedFileNames: map[string]bool{},
+		IgnorePatterns:   []string{},
+		MaxFileSize:      100 * 1024 * 1024,
+		Generated:        "summarize",
+	}
+	if err := run(config); err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	content, _ := os.ReadFile(dump)
+	for _, want := range []string{"_Vendored directory, content omitted._", "_symlink → src/b.go_", "# Install\n```sh"} {
+		if !strings.Contains(string(content), want) {
+			t.Fatalf("dump should contain %q, got:\n%s", want, content)
+		}
+	}
+	edited := strings.Replace(string(content), "package y\n", "package y // edited\n", 1)
+	if err := os.WriteFile(dump, []byte(edited), 0644); err != nil {
+		t.Fatalf("Failed to write edited dump: %v", err)
+	}
+
+	var applyErr error
+	captureStdout(t, func() {
+		applyErr = runApply([]string{"-i", inputDir, "--yes", dump})
+	})
+	if applyErr != nil {
+		t.Fatalf("runApply() error: %v", applyErr)
+	}
+
+	for path, want := range map[string]string{"y.go": "package y // edited\n", "src/b.go": "package b\n", "README.md": files["README.md"]} {
+		if got, _ := os.ReadFile(filepath.Join(inputDir, path)); string(got) != want {
+			t.Errorf("%s = %q; want %q", path, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(inputDir, "Install")); !os.IsNotExist(err) {
+		t.Error("a heading of the Markdown file should not be applied as a file")
+	}
+}
+
+func TestRunApplyRefusesOutline(t *testing.T) {
+	tempDir := t.TempDir()
+	inputDir := filepath.Join(tempDir, "input")
+	source := "package a\n\nfunc A() int {\n\treturn 1\n}\n"
+	if err := os.MkdirAll(inputDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(inputDir, "a.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	dump := filepath.Join(tempDir, "code.md")
+	config := &c2mConfig.Config{
+		InputFolder:      inputDir,
+		OutputMarkdown:   dump,
+		AllowedLanguages: map[string]bool{".go": true},
+		AllowedFileNames: map[string]bool{},
+		IgnorePatterns:   []string{},
+		MaxFileSize:      100 * 1024 * 1024,
+		Outline:          true,
+	}
+	if err := run(config); err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+
+	var applyErr error
+	captureStdout(t, func() {
+		applyErr = runApply([]string{"-i", inputDir, "--yes", dump})
+	})
+	if applyErr == nil {
+		t.Error("runApply() should refuse an outlined dump")
+	}
+	if got, _ := os.ReadFile(filepath.Join(inputDir, "a.go")); string(got) != source {
+		t.Errorf("a.go = %q; want %q", got, source)
+	}
+}
@@ -0,0 +1,587 @@
+package outline
+
+import (
+	"strings"
+)
+
+// braceSyntax describes the comments and strings of a language with braced
+// blocks, enough to find the blocks that belong to declarations.
+type braceSyntax struct {
+	lineComments []string
+	quotes       string
+	// templates are backquoted strings with ${...} substitutions.
+	templates bool
+	// textBlocks are Java's """ strings.
+	textBlocks bool
+	// asi ends statements at line breaks, as JavaScript does.
+	asi bool
+	// regexps are JavaScript's /.../ literals.
+	regexps bool
+	// imports are the keywords of top-level statements that are kept.
+	imports []string
+}
+
+var (
+	jsBraces = &braceSyntax{
+		lineComments: []string{"//"},
+		quotes:       `"'`,
+		templates:    true,
+		asi:          true,
+		regexps:      true,
+		imports:      []string{"import", "declare", "type", "interface", "function", "namespace"},
+	}
+	javaBraces = &braceSyntax{
+		lineComments: []string{"//"},
+		quotes:       `"'`,
+		textBlocks:   true,
+		imports:      []string{"package", "import"},
+	}
+	phpBraces = &braceSyntax{
+		lineComments: []string{"//", "#"},
+		quotes:       `"'`,
+		imports:      []string{"namespace", "use", "declare", "require", "require_once", "include", "include_once"},
+	}
+)
+
+var (
+	containerKeywords = []string{"class", "interface", "enum", "trait", "namespace", "module", "record"}
+	controlKeywords   = map[string]bool{
+		"if": true, "else": true, "elseif": true, "for": true, "foreach": true, "while": true, "do": true,
+		"switch": true, "try": true, "catch": true, "finally": true, "with": true, "synchronized": true,
+		"return": true,
+	}
+)
+
+// JavaScript renders imports, type declarations, classes and interfaces
+// with their members, and function and method signatures with their doc
+// comments. It also handles TypeScript.
+func JavaScript(content []byte) ([]byte, error) {
+	return outlineBraces(string(content), jsBraces), nil
+}
+
+// Java renders the package clause, imports, types with their fields and
+// method signatures, and doc comments.
+func Java(content []byte) ([]byte, error) {
+	return outlineBraces(string(content), javaBraces), nil
+}
+
+// PHP renders namespaces, use statements, classes, interfaces and traits
+// with their properties and method signatures, and functions.
+func PHP(content []byte) ([]byte, error) {
+	src := string(content)
+	var w writer
+	if strings.HasPrefix(src, "<?php") {
+		w.line("<?php", false)
+		src = src[len("<?php"):]
+	}
+	rest := outlineBraces(src, phpBraces)
+	if w.buf.Len() > 0 && len(rest) > 0 {
+		w.buf.WriteByte('\n')
+	}
+	w.buf.Write(rest)
+	return w.bytes(), nil
+}
+
+type braceOutliner struct {
+	*braceSyntax
+	src string
+	w   writer
+	// containers holds the indentation of the open classes and other
+	// containers whose members are outlined.
+	containers []string
+	// unit is the indentation of one level, found by indentUnit.
+	unit string
+}
+
+func outlineBraces(src string, s *braceSyntax) []byte {
+	o := &braceOutliner{braceSyntax: s, src: src}
+	o.run()
+	return o.w.bytes()
+}
+
+func (o *braceOutliner) run() {
+	start, depth := 0, 0
+	for i := 0; i < len(o.src); {
+		if next := o.skip(i); next > i {
+			i = next
+			continue
+		}
+		switch c := o.src[i]; {
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			if depth > 0 {
+				depth--
+			}
+		case c == ';' && depth == 0:
+			o.statement(start, i+1)
+			start, i = o.nextStart(i+1), o.nextStart(i+1)
+			continue
+		case c == '\n' && depth == 0 && o.asi && o.endsStatement(start, i):
+			o.statement(start, i)
+			start = i + 1
+		case c == '{' && depth > 0:
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case c == '{':
+			switch o.classify(o.src[start:i]) {
+			case "expression":
+				depth++
+				i++
+				continue
+			case "container":
+				indent := o.indent(start)
+				if len(o.containers) > 0 && o.startsMidLine(start) {
+					indent = o.containers[len(o.containers)-1] + o.indentUnit()
+				}
+				o.w.line(o.member(start, strings.TrimRight(o.text(start, i), " \t\r\n"))+" {", o.blankBefore(start))
+				o.containers = append(o.containers, indent)
+			case "function":
+				o.w.line(o.member(start, strings.TrimRight(o.text(start, i), " \t\r\n"))+" { ... }", o.blankBefore(start))
+				i = o.blockEnd(i)
+				start, i = o.nextStart(i), o.nextStart(i)
+				continue
+			default:
+				i = o.blockEnd(i)
+				start, i = o.nextStart(i), o.nextStart(i)
+				continue
+			}
+			start, i = o.nextStart(i+1), o.nextStart(i+1)
+			continue
+		case c == '}':
+			o.statement(start, i)
+			if n := len(o.containers); n > 0 {
+				o.w.line(o.containers[n-1]+"}", false)
+				o.containers = o.containers[:n-1]
+			}
+			start, i = o.nextStart(i+1), o.nextStart(i+1)
+			continue
+		}
+		i++
+	}
+	o.statement(start, len(o.src))
+}
+
+// statement writes the statement src[start:end] if it is kept at the
+// current level: imports at the top level and all members in containers.
+func (o *braceOutliner) statement(start, end int) {
+	code := strings.TrimSpace(o.code(start, end))
+	if code == "" || code == ";" {
+		return
+	}
+	text := strings.TrimRight(o.text(start, end), " \t\r\n")
+	if len(o.containers) > 0 {
+		if !strings.Contains(code, "=") || !strings.Contains(code, "\n") {
+			o.w.line(o.member(start, text), o.blankBefore(start))
+		}
+		return
+	}
+
+	word := firstWord(strings.TrimPrefix(code, "export "))
+	switch {
+	case hasWord(o.imports, word):
+	case o.asi && strings.HasPrefix(code, "export") && !strings.Contains(code, "\n"):
+	case o.asi && strings.Contains(code, "require(") && !strings.Contains(code, "\n"):
+	default:
+		return
+	}
+	o.w.line(text, o.blankBefore(start))
+}
+
+// classify tells what the block opened after head is: a "container" whose
+// members are outlined, a "function" whose body is elided, an "expression"
+// such as an object literal that is part of the statement, or a "block"
+// that is skipped with its statement.
+func (o *braceOutliner) classify(head string) string {
+	code := strings.TrimSpace(o.stripComments(head))
+	if code == "" {
+		return "block"
+	}
+	last := code[len(code)-1]
+	if strings.IndexByte("=(,:?[!&|+-*%<", last) >= 0 || strings.HasSuffix(code, "return") {
+		return "expression"
+	}
+	if word := firstWord(code); word == "import" || code == "export" || code == "export type" {
+		return "expression"
+	}
+	code = stripAnnotations(code)
+	if controlKeywords[firstWord(code)] {
+		return "block"
+	}
+
+	signature := code
+	if paren := strings.IndexByte(code, '('); paren >= 0 {
+		signature = code[:paren]
+	}
+	fields := strings.Fields(signature)
+	for i, word := range fields {
+		if !hasWord(containerKeywords, word) {
+			continue
+		}
+		// The keyword has to name a declaration, unlike in "void record(".
+		if word == "class" || (i+1 < len(fields) && (isIdentifierByte(fields[i+1][0]) || fields[i+1][0] == '"' || fields[i+1][0] == '\'')) {
+			return "container"
+		}
+	}
+	if strings.Contains(code, "(") && (!hasAssignment(code) || strings.Contains(code, "=>") || strings.Contains(code, "function")) {
+		return "function"
+	}
+	if strings.HasSuffix(code, "=>") {
+		return "function"
+	}
+	return "block"
+}
+
+// endsStatement applies automatic semicolon insertion loosely: a line
+// break ends the statement unless the line or the next one continues it.
+func (o *braceOutliner) endsStatement(start, i int) bool {
+	code := strings.TrimSpace(o.code(start, i))
+	if stripAnnotations(code) == "" {
+		return false
+	}
+	if strings.IndexByte(",=+-*%&|^!?:.([{", code[len(code)-1]) >= 0 || strings.HasSuffix(code, "=>") {
+		return false
+	}
+	next := strings.TrimLeft(o.src[i:], " \t\r\n")
+	if next == "" {
+		return true
+	}
+	if strings.IndexByte(".?:|&=>{)],", next[0]) >= 0 {
+		return false
+	}
+	word := firstWord(next)
+	return word != "extends" && word != "implements"
+}
+
+// skip returns the index after the comment or string starting at i, or i.
+func (o *braceOutliner) skip(i int) int {
+	if i >= len(o.src) {
+		return i
+	}
+	rest := o.src[i:]
+	if o.isLineComment(rest) {
+		if end := strings.IndexByte(rest, '\n'); end >= 0 {
+			return i + end
+		}
+		return len(o.src)
+	}
+	if strings.HasPrefix(rest, "/*") {
+		if end := strings.Index(rest[2:], "*/"); end >= 0 {
+			return i + end + 4
+		}
+		return len(o.src)
+	}
+	if o.textBlocks && strings.HasPrefix(rest, `"""`) {
+		if end := strings.Index(rest[3:], `"""`); end >= 0 {
+			return i + end + 6
+		}
+		return len(o.src)
+	}
+	if o.templates && rest[0] == '`' {
+		return i + templateEnd(rest)
+	}
+	if o.regexps && rest[0] == '/' && o.startsRegexp(i) {
+		if end := regexpEnd(rest); end > 0 {
+			return i + end
+		}
+	}
+	if strings.IndexByte(o.quotes, rest[0]) >= 0 {
+		for j := 1; j < len(rest); j++ {
+			switch rest[j] {
+			case '\\':
+				j++
+			case rest[0]:
+				return i + j + 1
+			case '\n':
+				return i + j
+			}
+		}
+		return len(o.src)
+	}
+	return i
+}
+
+func (o *braceOutliner) isLineComment(rest string) bool {
+	for _, prefix := range o.lineComments {
+		if strings.HasPrefix(rest, prefix) && !strings.HasPrefix(rest, "#[") {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *braceOutliner) isComment(rest string) bool {
+	return o.isLineComment(rest) || strings.HasPrefix(rest, "/*")
+}
+
+// startsRegexp tells a regular expression literal at i apart from a
+// division by the code before it.
+func (o *braceOutliner) startsRegexp(i int) bool {
+	before := strings.TrimRight(o.src[:i], " \t\r\n")
+	if before == "" || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", before[len(before)-1]) >= 0 {
+		return true
+	}
+	start := len(before)
+	for start > 0 && isIdentifierByte(before[start-1]) {
+		start--
+	}
+	switch before[start:] {
+	case "return", "typeof", "case", "in", "of", "instanceof", "new", "delete", "void", "throw", "yield", "await":
+		return true
+	}
+	return false
+}
+
+// regexpEnd returns the length of the regular expression literal at the
+// start of rest, or 0 if it does not end on the same line.
+func regexpEnd(rest string) int {
+	inClass := false
+	for j := 1; j < len(rest); j++ {
+		switch rest[j] {
+		case '\\':
+			j++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				return j + 1
+			}
+		case '\n':
+			return 0
+		}
+	}
+	return 0
+}
+
+// templateEnd returns the length of the template literal at the start of
+// rest, following nested braces of ${...} substitutions.
+func templateEnd(rest string) int {
+	depth := 0
+	for j := 1; j < len(rest); j++ {
+		switch {
+		case rest[j] == '\\':
+			j++
+		case strings.HasPrefix(rest[j:], "${"):
+			depth++
+			j++
+		case rest[j] == '}' && depth > 0:
+			depth--
+		case rest[j] == '`' && depth == 0:
+			return j + 1
+		}
+	}
+	return len(rest)
+}
+
+// blockEnd returns the index after the brace closing the one at i.
+func (o *braceOutliner) blockEnd(i int) int {
+	depth := 0
+	for i < len(o.src) {
+		if next := o.skip(i); next > i {
+			i = next
+			continue
+		}
+		switch o.src[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+		i++
+	}
+	return len(o.src)
+}
+
+// nextStart moves the start of the next statement past the rest of the
+// current line when it only holds white space or a comment, so that
+// trailing comments are not taken for doc comments.
+func (o *braceOutliner) nextStart(i int) int {
+	j := i
+	for j < len(o.src) && (o.src[j] == ' ' || o.src[j] == '\t' || o.src[j] == '\r') {
+		j++
+	}
+	if next := o.skip(j); next > j && o.isLineComment(o.src[j:]) {
+		j = next
+	}
+	if j < len(o.src) && o.src[j] == '\n' {
+		return j + 1
+	}
+	return i
+}
+
+// text returns src[start:end] beginning at the first line with content,
+// including its indentation unless the statement starts mid-line.
+func (o *braceOutliner) text(start, end int) string {
+	text := o.src[start:end]
+	lead := len(text) - len(strings.TrimLeft(text, " \t\r\n"))
+	if nl := strings.LastIndexByte(text[:lead], '\n'); nl >= 0 {
+		return text[nl+1:]
+	}
+	if start == 0 || o.src[start-1] == '\n' {
+		return text
+	}
+	return text[lead:]
+}
+
+// member indents the text of a member that starts on the line of its
+// container's opening brace one level deeper than the container.
+func (o *braceOutliner) member(start int, text string) string {
+	if len(o.containers) == 0 || !o.startsMidLine(start) {
+		return text
+	}
+	return o.containers[len(o.containers)-1] + o.indentUnit() + text
+}
+
+// startsMidLine reports whether the statement at start begins on a line
+// after other code.
+func (o *braceOutliner) startsMidLine(start int) bool {
+	text := o.src[start:]
+	lead := text[:len(text)-len(strings.TrimLeft(text, " \t\r\n"))]
+	return start > 0 && o.src[start-1] != '\n' && !strings.Contains(lead, "\n")
+}
+
+// indentUnit returns the indentation of the first indented line, or four
+// spaces if there is none.
+func (o *braceOutliner) indentUnit() string {
+	if o.unit != "" {
+		return o.unit
+	}
+	o.unit = "    "
+	for _, line := range strings.Split(o.src, "\n") {
+		if indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]; indent != "" && strings.TrimSpace(line) != "" {
+			o.unit = indent
+			break
+		}
+	}
+	return o.unit
+}
+
+// blankBefore reports whether a blank line precedes the statement at start.
+func (o *braceOutliner) blankBefore(start int) bool {
+	text := o.src[start:]
+	lead := text[:len(text)-len(strings.TrimLeft(text, " \t\r\n"))]
+	newlines := strings.Count(lead, "\n")
+	if start == 0 || o.src[start-1] == '\n' {
+		return newlines >= 1
+	}
+	return newlines >= 2
+}
+
+// indent returns the indentation of the first code line of the statement.
+func (o *braceOutliner) indent(start int) string {
+	i := start
+	for i < len(o.src) {
+		if next := o.skip(i); next > i && o.isComment(o.src[i:]) {
+			i = next
+			continue
+		}
+		if c := o.src[i]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			break
+		}
+		i++
+	}
+	lineStart := strings.LastIndexByte(o.src[:i], '\n') + 1
+	line := o.src[lineStart:i]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// code returns src[start:end] without comments.
+func (o *braceOutliner) code(start, end int) string {
+	return o.stripComments(o.src[start:end])
+}
+
+func (o *braceOutliner) stripComments(text string) string {
+	sub := &braceOutliner{braceSyntax: o.braceSyntax, src: text}
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		next := sub.skip(i)
+		if next == i {
+			b.WriteByte(text[i])
+			i++
+			continue
+		}
+		if sub.isComment(text[i:]) {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(text[i:next])
+		}
+		i = next
+	}
+	return b.String()
+}
+
+// stripAnnotations removes leading annotations, decorators and attributes
+// such as @Override, @Component({...}) or #[Route('/')].
+func stripAnnotations(code string) string {
+	for strings.HasPrefix(code, "#[") {
+		end := strings.IndexByte(code, ']')
+		if end < 0 {
+			return code
+		}
+		code = strings.TrimSpace(code[end+1:])
+	}
+	for strings.HasPrefix(code, "@") {
+		end := 1
+		for end < len(code) && (isIdentifierByte(code[end]) || code[end] == '.') {
+			end++
+		}
+		if end < len(code) && code[end] == '(' {
+			depth := 0
+			for ; end < len(code); end++ {
+				if code[end] == '(' {
+					depth++
+				} else if code[end] == ')' {
+					depth--
+					if depth == 0 {
+						end++
+						break
+					}
+				}
+			}
+		}
+		code = strings.TrimSpace(code[end:])
+	}
+	return code
+}
+
+// hasAssignment reports whether code assigns outside parentheses, as in
+// "const x = new Foo() {".
+func hasAssignment(code string) bool {
+	depth := 0
+	for i := 0; i < len(code); i++ {
+		switch code[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '=':
+			if depth != 0 {
+				continue
+			}
+			if i+1 < len(code) && (code[i+1] == '=' || code[i+1] == '>') {
+				i++
+				continue
+			}
+			if i > 0 && strings.IndexByte("=!<>", code[i-1]) >= 0 {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func hasWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package outline
+
+import (
+	"testing"
+)
+
+func TestJavaScript(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"imports and exported declarations",
+			"import { a } from './a'\nimport type { B } from \"./b\";\nconst c = require('c')\nconst internal = 1\n\nexport type Mode = 'a' | 'b'\n\nexport type Shape = {\n  kind: string\n}\n\napp.get('/', (req, res) => {\n  res.send('{')\n})\n",
+			"import { a } from './a'\nimport type { B } from \"./b\";\nconst c = require('c')\n\nexport type Mode = 'a' | 'b'\n\nexport type Shape = {\n  kind: string\n}\n",
+		},
+		{
+			"function bodies are elided",
+			"/** Adds. */\nexport function add(a: number, b: number): number {\n  return a + b\n}\n\nexport const twice = async (x: number) => {\n  return `${x} {`\n}\n",
+			"/** Adds. */\nexport function add(a: number, b: number): number { ... }\n\nexport const twice = async (x: number) => { ... }\n",
+		},
+		{
+			"classes and interfaces keep their members",
+			"// Widget docs.\n@Component({\n  selector: 'w',\n})\nexport class Widget extends Base {\n  private count = 0\n  static create(): Widget {\n    return new Widget()\n  }\n\n  handle = (e: Event) => {\n    if (e) { return }\n  }\n}\n\ninterface Props {\n  name: string\n  nested: { a: number }\n}\n",
+			"// Widget docs.\n@Component({\n  selector: 'w',\n})\nexport class Widget extends Base {\n  private count = 0\n  static create(): Widget { ... }\n\n  handle = (e: Event) => { ... }\n}\n\ninterface Props {\n  name: string\n  nested: { a: number }\n}\n",
+		},
+		{
+			"members of one-line containers are indented",
+			"export interface I { a: number; b(): void; }\n\nexport class C {\n  static D = class { run() { go() } }\n  m(): void {}\n}\n",
+			"export interface I {\n  a: number;\n  b(): void;\n}\n\nexport class C {\n  static D = class {\n    run() { ... }\n  }\n  m(): void { ... }\n}\n",
+		},
+		{
+			"regular expressions with braces",
+			"const re = /[{]/;\nconst half = total / 2 / count\nexport function match(s: string): boolean {\n  return /^}+$/.test(s) && s.split(/\\//).length > 1\n}\n",
+			"export function match(s: string): boolean { ... }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JavaScript([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("JavaScript() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("JavaScript() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJava(t *testing.T) {
+	input := "package com.example;\n\nimport java.util.List;\n\n/** Service. */\n@Service\npublic class UserService {\n    private final Repo repo;\n    private static final Map<String, Integer> M = new HashMap<>() {{\n        put(\"a\", 1);\n    }};\n\n    static {\n        init();\n    }\n\n    /** Finds a user. */\n    @Override\n    public User find(String id) throws IOException {\n        return repo.find(\"}\");\n    }\n\n    void record(String s) {\n    }\n\n    public record Point(int x, int y) {\n        double len() { return 0; }\n    }\n}\n"
+	want := "package com.example;\n\nimport java.util.List;\n\n/** Service. */\n@Service\npublic class UserService {\n    private final Repo repo;\n\n    /** Finds a user. */\n    @Override\n    public User find(String id) throws IOException { ... }\n\n    void record(String s) { ... }\n\n    public record Point(int x, int y) {\n        double len() { ... }\n    }\n}\n"
+
+	got, err := Java([]byte(input))
+	if err != nil {
+		t.Fatalf("Java() error: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Java() =\n%q\nwant\n%q", got, want)
+	}
+
+	input = "class A {\n    interface I { void g(); }\n    enum E { X, Y }\n}\n"
+	want = "class A {\n    interface I {\n        void g();\n    }\n    enum E {\n        X, Y\n    }\n}\n"
+	if got, _ := Java([]byte(input)); string(got) != want {
+		t.Errorf("Java() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestPHP(t *testing.T) {
+	input := "<?php\n\nnamespace App;\n\nuse App\\Models\\User;\n\n#[Route('/users')]\nfinal class UserController extends Controller\n{\n    private array $cache = [];\n\n    public function show(int $id): ?User\n    {\n        # {\n        return User::find($id);\n    }\n\n    abstract protected function handle(): void;\n}\n\nfunction helper($x) {\n    return $x;\n}\n\n$app->run();\n"
+	want := "<?php\n\nnamespace App;\n\nuse App\\Models\\User;\n\n#[Route('/users')]\nfinal class UserController extends Controller {\n    private array $cache = [];\n\n    public function show(int $id): ?User { ... }\n\n    abstract protected function handle(): void;\n}\n\nfunction helper($x) { ... }\n"
+
+	got, err := PHP([]byte(input))
+	if err != nil {
+		t.Fatalf("PHP() error: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("PHP() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestUnbalancedBraces(t *testing.T) {
+	for name, outline := range map[string]func([]byte) ([]byte, error){"JavaScript": JavaScript, "Java": Java, "PHP": PHP} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := outline([]byte("foo();\n}")); err != nil {
+				t.Errorf("%s() error: %v", name, err)
+			}
+		})
+	}
+}
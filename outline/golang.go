@@ -0,0 +1,205 @@
+package outline
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+)
+
+// gofmt prints nodes the way gofmt formats them.
+var gofmt = &printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+
+// Go renders the skeleton of a Go file: everything up to the package clause,
+// imports, type declarations, exported constants and variables and the
+// signatures of exported functions and methods with their doc comments.
+func Go(content []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(content[:fset.Position(file.Name.End()).Offset])
+	buf.WriteString("\n")
+
+	for _, decl := range file.Decls {
+		node, ok := outlineDecl(decl)
+		if !ok {
+			continue
+		}
+		buf.WriteString("\n")
+		comments := commentsWithin(file, decl)
+		if vars, ok := node.(*ast.GenDecl); ok && vars.Tok == token.VAR {
+			comments = keptSpecComments(comments, decl.(*ast.GenDecl), vars)
+		}
+		commented := &printer.CommentedNode{Node: node, Comments: comments}
+		var printed bytes.Buffer
+		if err := gofmt.Fprint(&printed, fset, commented); err != nil {
+			return nil, err
+		}
+		// A trailing line comment is printed with its own line break.
+		buf.Write(bytes.TrimRight(printed.Bytes(), "\n"))
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+func outlineDecl(decl ast.Decl) (ast.Decl, bool) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil, false
+		}
+		signature := *d
+		signature.Body = nil
+		return &signature, true
+	case *ast.GenDecl:
+		switch d.Tok {
+		case token.IMPORT, token.TYPE:
+			return d, true
+		case token.CONST:
+			return d, hasExportedName(d)
+		case token.VAR:
+			return exportedVars(d)
+		}
+	}
+	return nil, false
+}
+
+func hasExportedName(d *ast.GenDecl) bool {
+	for _, spec := range d.Specs {
+		for _, name := range spec.(*ast.ValueSpec).Names {
+			if name.IsExported() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// exportedVars keeps exported variables, dropping initial values that span
+// several lines such as composite or function literals.
+func exportedVars(d *ast.GenDecl) (ast.Decl, bool) {
+	var specs []ast.Spec
+	for _, spec := range d.Specs {
+		vs := spec.(*ast.ValueSpec)
+		if !hasExported(vs.Names) {
+			continue
+		}
+		if hasLiteral(vs.Values) {
+			if vs.Type == nil {
+				continue
+			}
+			typed := *vs
+			typed.Values = nil
+			typed.Comment = nil
+			vs = &typed
+		}
+		specs = append(specs, vs)
+	}
+	if len(specs) == 0 {
+		return nil, false
+	}
+	vars := *d
+	vars.Specs = specs
+	// A doc comment of the only spec stays in the group, as it would be
+	// printed between var and the name otherwise.
+	if len(specs) == 1 && specs[0].(*ast.ValueSpec).Doc == nil {
+		vars.Lparen, vars.Rparen = token.NoPos, token.NoPos
+	} else if vars.Rparen.IsValid() {
+		// The group closes after the last kept spec, not after dropped ones.
+		_, vars.Rparen = specExtent(specs[len(specs)-1].(*ast.ValueSpec))
+	}
+	return &vars, true
+}
+
+func hasExported(names []*ast.Ident) bool {
+	for _, name := range names {
+		if name.IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLiteral(values []ast.Expr) bool {
+	for _, value := range values {
+		switch value.(type) {
+		case *ast.CompositeLit, *ast.FuncLit:
+			return true
+		}
+	}
+	return false
+}
+
+// commentsWithin returns the comments of decl including its doc comment,
+// leaving out those in function bodies.
+func commentsWithin(file *ast.File, decl ast.Decl) []*ast.CommentGroup {
+	start, end := decl.Pos(), decl.End()
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Doc != nil {
+			start = d.Doc.Pos()
+		}
+		if d.Body != nil {
+			end = d.Body.Lbrace
+		}
+	case *ast.GenDecl:
+		if d.Doc != nil {
+			start = d.Doc.Pos()
+		}
+	}
+
+	var comments []*ast.CommentGroup
+	for _, c := range file.Comments {
+		if c.Pos() >= start && c.End() <= end {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// keptSpecComments drops the comments of specs left out of outline, of the
+// initial values dropped from the outlined specs and those after the last
+// outlined spec.
+func keptSpecComments(comments []*ast.CommentGroup, decl, outlined *ast.GenDecl) []*ast.CommentGroup {
+	var kept []*ast.CommentGroup
+	for _, c := range comments {
+		if withinSpec(c, decl.Specs) && !withinSpec(c, outlined.Specs) {
+			continue
+		}
+		if _, end := specExtent(outlined.Specs[len(outlined.Specs)-1].(*ast.ValueSpec)); c.Pos() >= end && c.End() <= decl.End() {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// withinSpec reports whether c is a comment of one of the value specs,
+// including their doc and line comments.
+func withinSpec(c *ast.CommentGroup, specs []ast.Spec) bool {
+	for _, spec := range specs {
+		start, end := specExtent(spec.(*ast.ValueSpec))
+		if c.Pos() >= start && c.End() <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// specExtent returns the positions of a value spec including its doc and
+// line comments.
+func specExtent(vs *ast.ValueSpec) (start, end token.Pos) {
+	start, end = vs.Pos(), vs.End()
+	if vs.Doc != nil {
+		start = vs.Doc.Pos()
+	}
+	if vs.Comment != nil {
+		end = vs.Comment.End()
+	}
+	return start, end
+}
@@ -0,0 +1,58 @@
+package outline
+
+import (
+	"bytes"
+	"fmt"
+)
+
+var renderers = map[string]func([]byte) ([]byte, error){
+	"go":   Go,
+	"py":   Python,
+	"js":   JavaScript,
+	"jsx":  JavaScript,
+	"ts":   JavaScript,
+	"tsx":  JavaScript,
+	"java": Java,
+	"php":  PHP,
+}
+
+// IsSupported reports whether an outline can be rendered for the fence
+// language.
+func IsSupported(fence string) bool {
+	_, ok := renderers[fence]
+	return ok
+}
+
+// Render returns the outline of content written in the fence language.
+func Render(content []byte, fence string) (outline []byte, err error) {
+	render, ok := renderers[fence]
+	if !ok {
+		return nil, fmt.Errorf("no outline for %q", fence)
+	}
+	// Outlines are best effort: content the outliners trip over fails like
+	// content that does not parse.
+	defer func() {
+		if r := recover(); r != nil {
+			outline, err = nil, fmt.Errorf("outlining %s: %v", fence, r)
+		}
+	}()
+	return render(content)
+}
+
+// writer collects outline lines, keeping single blank lines where the
+// source separated declarations.
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) line(text string, blankBefore bool) {
+	if blankBefore && w.buf.Len() > 0 && !bytes.HasSuffix(w.buf.Bytes(), []byte("\n\n")) {
+		w.buf.WriteByte('\n')
+	}
+	w.buf.WriteString(text)
+	w.buf.WriteByte('\n')
+}
+
+func (w *writer) bytes() []byte {
+	return w.buf.Bytes()
+}
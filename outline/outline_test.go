@@ -0,0 +1,97 @@
+package outline
+
+import (
+	"testing"
+)
+
+func TestGo(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"package clause and imports",
+			"//go:build linux\n\n// Package x does things.\npackage x\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nimport \"strings\"\n",
+			"//go:build linux\n\n// Package x does things.\npackage x\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nimport \"strings\"\n",
+		},
+		{
+			"exported function bodies are elided",
+			"package x\n\n// Add returns the sum.\nfunc Add(a, b int) int {\n\t// inside\n\treturn a + b\n}\n\nfunc helper() {}\n",
+			"package x\n\n// Add returns the sum.\nfunc Add(a, b int) int\n",
+		},
+		{
+			"exported methods on any receiver",
+			"package x\n\ntype t struct{}\n\n// Run runs.\nfunc (t *t) Run() error { return nil }\n\nfunc (t *t) stop() {}\n",
+			"package x\n\ntype t struct{}\n\n// Run runs.\nfunc (t *t) Run() error\n",
+		},
+		{
+			"type declarations keep field comments",
+			"package x\n\n// Config holds settings.\ntype Config struct {\n\tName string // display name\n\tsize int\n}\n\ntype Reader interface {\n\tRead(p []byte) (int, error)\n}\n",
+			"package x\n\n// Config holds settings.\ntype Config struct {\n\tName string // display name\n\tsize int\n}\n\ntype Reader interface {\n\tRead(p []byte) (int, error)\n}\n",
+		},
+		{
+			"exported constants and variables",
+			"package x\n\nconst (\n\tA = iota\n\tb\n)\n\nconst c = 1\n\nvar ErrX = errors.New(\"x\")\n\nvar Table = map[string]int{\n\t\"a\": 1,\n}\n\nvar Typed []string = []string{\"a\"}\n\nvar y = 2\n",
+			"package x\n\nconst (\n\tA = iota\n\tb\n)\n\nvar ErrX = errors.New(\"x\")\n\nvar Typed []string\n",
+		},
+		{
+			"grouped variable left alone with its doc comment dropped",
+			"package x\n\nvar (\n\t// X is x.\n\tX = map[string]int{\n\t\t\"a\": 1,\n\t}\n\tY int = 3\n\tz = 1\n)\n",
+			"package x\n\nvar Y int = 3\n",
+		},
+		{
+			"grouped variable kept alone with its doc comment",
+			"package x\n\nvar (\n\t// A doc\n\tA = 1 // a\n\tb = 2\n)\n",
+			"package x\n\nvar (\n\t// A doc\n\tA = 1 // a\n)\n",
+		},
+		{
+			"grouped variable kept alone after trailing comments",
+			"package x\n\nvar (\n\tA = 1 // a\n\tb = 2\n\t// trailing\n)\n",
+			"package x\n\nvar A = 1 // a\n",
+		},
+		{
+			"grouped variables drop comments of dropped specs and values",
+			"package x\n\nvar (\n\t// A is a.\n\tA = 1\n\t// X is x.\n\tX = map[string]int{\n\t\t\"a\": 1, // one\n\t}\n\t// B is b.\n\tB []int = []int{\n\t\t1, // first\n\t} // bs\n)\n",
+			"package x\n\nvar (\n\t// A is a.\n\tA = 1\n\n\t// B is b.\n\tB []int\n)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Go([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Go() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Go() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoParseError(t *testing.T) {
+	if _, err := Go([]byte("package x\n\nfunc {")); err == nil {
+		t.Error("Go() should fail on invalid source")
+	}
+}
+
+func TestRender(t *testing.T) {
+	if _, err := Render([]byte("package x\n"), "go"); err != nil {
+		t.Errorf("Render() error: %v", err)
+	}
+	if _, err := Render([]byte("# Title\n"), "md"); err == nil {
+		t.Error("Render() should fail for unsupported languages")
+	}
+
+	renderers["broken"] = func([]byte) ([]byte, error) { panic("broken") }
+	defer delete(renderers, "broken")
+	if _, err := Render([]byte("x\n"), "broken"); err == nil {
+		t.Error("Render() should turn a panic of the outliner into an error")
+	}
+	for fence, want := range map[string]bool{"go": true, "py": true, "tsx": true, "java": true, "php": true, "md": false, "rust": false} {
+		if got := IsSupported(fence); got != want {
+			t.Errorf("IsSupported(%q) = %v; want %v", fence, got, want)
+		}
+	}
+}
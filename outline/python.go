@@ -0,0 +1,217 @@
+package outline
+
+import (
+	"strings"
+)
+
+// pythonLine is a logical line: physical lines joined by open brackets,
+// backslashes or multiline strings.
+type pythonLine struct {
+	indent int
+	text   string
+	// header is the text up to the first colon outside brackets and strings,
+	// which ends the header of def and class statements.
+	header      string
+	blankBefore bool
+}
+
+// Python renders imports, classes with their docstrings and annotated
+// attributes, and function and method signatures with their decorators and
+// docstrings. Bodies are replaced by "...".
+func Python(content []byte) ([]byte, error) {
+	lines := pythonLines(string(content))
+	var w writer
+	var classes []pythonClass
+	skipDeeper := -1
+
+	// closeClass elides the body of a class without outlined members.
+	closeClass := func() {
+		c := classes[len(classes)-1]
+		if w.buf.Len() == c.mark {
+			w.line(c.bodyIndent+"...", false)
+		}
+		classes = classes[:len(classes)-1]
+	}
+
+	if len(lines) > 0 && lines[0].indent == 0 && isStringStatement(lines[0].text) {
+		w.line(lines[0].text, false)
+		lines = lines[1:]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		if skipDeeper >= 0 {
+			if l.indent > skipDeeper {
+				continue
+			}
+			skipDeeper = -1
+		}
+		for len(classes) > 0 && l.indent <= classes[len(classes)-1].indent {
+			closeClass()
+		}
+
+		word := firstWord(l.text)
+		if word == "async" {
+			word = firstWord(strings.TrimSpace(l.text)[len("async"):])
+		}
+		switch {
+		case word == "import" || word == "from":
+			w.line(l.text, l.blankBefore)
+			continue
+		case strings.HasPrefix(strings.TrimSpace(l.text), "@"):
+			w.line(l.text, l.blankBefore)
+			continue
+		case (word == "def" || word == "class") && l.header != "":
+		case len(classes) > 0 && isAnnotatedAttribute(l.text):
+			w.line(l.text, l.blankBefore)
+			continue
+		default:
+			skipDeeper = l.indent
+			continue
+		}
+
+		if body := strings.TrimSpace(strings.TrimPrefix(l.text, l.header)); body != "" && body[0] != '#' {
+			w.line(l.header+" ...", l.blankBefore)
+			continue
+		}
+		w.line(l.header, l.blankBefore)
+		bodyIndent := strings.Repeat(" ", l.indent+4)
+		if i+1 < len(lines) && lines[i+1].indent > l.indent {
+			bodyIndent = lines[i+1].text[:lines[i+1].indent]
+			if isStringStatement(lines[i+1].text) {
+				w.line(lines[i+1].text, false)
+				i++
+			}
+		}
+		if word == "class" {
+			classes = append(classes, pythonClass{l.indent, bodyIndent, w.buf.Len()})
+			continue
+		}
+		w.line(bodyIndent+"...", false)
+		skipDeeper = l.indent
+	}
+	for len(classes) > 0 {
+		closeClass()
+	}
+	return w.bytes(), nil
+}
+
+// pythonClass is a class whose body is being outlined; mark is the length
+// of the output after its header and docstring.
+type pythonClass struct {
+	indent     int
+	bodyIndent string
+	mark       int
+}
+
+func pythonLines(src string) []pythonLine {
+	var lines []pythonLine
+	start, depth, colon := 0, 0, -1
+	blank := false
+
+	finish := func(end int) {
+		text := strings.TrimRight(src[start:end], " \t\r")
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case trimmed == "":
+			blank = true
+		case trimmed[0] == '#':
+		default:
+			l := pythonLine{
+				indent:      len(text) - len(strings.TrimLeft(text, " \t")),
+				text:        text,
+				blankBefore: blank,
+			}
+			if colon >= 0 {
+				l.header = src[start : colon+1]
+			}
+			lines = append(lines, l)
+			blank = false
+		}
+		start, depth, colon = end+1, 0, -1
+	}
+
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '#':
+			for i+1 < len(src) && src[i+1] != '\n' {
+				i++
+			}
+		case '"', '\'':
+			i = pythonStringEnd(src, i) - 1
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth > 0 {
+				depth--
+			}
+		case ':':
+			if depth == 0 && colon < 0 {
+				colon = i
+			}
+		case '\\':
+			i++
+		case '\n':
+			if depth == 0 {
+				finish(i)
+			}
+		}
+	}
+	if start < len(src) {
+		finish(len(src))
+	}
+	return lines
+}
+
+// pythonStringEnd returns the index after the string literal starting at i.
+// Single-quoted strings end at the end of the line when unterminated.
+func pythonStringEnd(src string, i int) int {
+	quote := src[i : i+1]
+	if strings.HasPrefix(src[i:], quote+quote+quote) {
+		quote = src[i : i+3]
+	}
+	for j := i + len(quote); j < len(src); j++ {
+		switch {
+		case src[j] == '\\':
+			j++
+		case strings.HasPrefix(src[j:], quote):
+			return j + len(quote)
+		case src[j] == '\n' && len(quote) == 1:
+			return j
+		}
+	}
+	return len(src)
+}
+
+func firstWord(text string) string {
+	text = strings.TrimSpace(text)
+	end := 0
+	for end < len(text) && isIdentifierByte(text[end]) {
+		end++
+	}
+	return text[:end]
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b == '$' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// isStringStatement reports whether text consists of a string literal only,
+// as docstrings do.
+func isStringStatement(text string) bool {
+	text = strings.TrimSpace(text)
+	prefix := strings.IndexAny(text, `"'`)
+	if prefix < 0 || prefix > 2 || strings.Trim(text[:prefix], "rRuUbBfF") != "" {
+		return false
+	}
+	end := pythonStringEnd(text, prefix)
+	rest := strings.TrimSpace(text[end:])
+	return rest == "" || rest[0] == '#'
+}
+
+// isAnnotatedAttribute matches class attributes such as "count: int = 0".
+func isAnnotatedAttribute(text string) bool {
+	word := firstWord(text)
+	rest := strings.TrimSpace(strings.TrimSpace(text)[len(word):])
+	return word != "" && strings.HasPrefix(rest, ":") && !strings.Contains(text, "\n")
+}
@@ -0,0 +1,41 @@
+package outline
+
+import (
+	"testing"
+)
+
+func TestPython(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"module docstring and imports",
+			"#!/usr/bin/env python3\n\"\"\"Tools.\"\"\"\n\nimport os\nfrom typing import (\n    Dict,\n)\n\nVALUE = 1\n",
+			"\"\"\"Tools.\"\"\"\n\nimport os\nfrom typing import (\n    Dict,\n)\n",
+		},
+		{
+			"function bodies are elided",
+			"def add(a: int,\n        b: int = 2) -> int:\n    \"\"\"Add numbers.\"\"\"\n    def inner():\n        pass\n    return a + b\n\n\nasync def fetch(url=\"http://x:80\"): return None\n",
+			"def add(a: int,\n        b: int = 2) -> int:\n    \"\"\"Add numbers.\"\"\"\n    ...\n\nasync def fetch(url=\"http://x:80\"): ...\n",
+		},
+		{
+			"classes keep methods, decorators and annotated attributes",
+			"@dataclass\nclass Point(Base):\n    '''A point.'''\n\n    x: int = 0\n    count = 0\n\n    @property\n    def norm(self) -> float:\n        s = \"\"\"\ndef fake():\n\"\"\"\n        return 0.0\n\n    class Meta:\n        ordering = ['x']\n\n\nif __name__ == '__main__':\n    main()\n",
+			"@dataclass\nclass Point(Base):\n    '''A point.'''\n\n    x: int = 0\n\n    @property\n    def norm(self) -> float:\n        ...\n\n    class Meta:\n        ...\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Python([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Python() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Python() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
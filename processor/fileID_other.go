@@ -0,0 +1,23 @@
+//go:build windows || plan9
+
+package processor
+
+import "path/filepath"
+
+// fileID identifies a file independently of the path it is reached by. The
+// device and inode are not available here, so the resolved path stands in.
+type fileID struct {
+	path string
+}
+
+func statID(path string) (fileID, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fileID{}, err
+	}
+	abs, err := filepath.Abs(resolved)
+	if err != nil {
+		return fileID{}, err
+	}
+	return fileID{path: abs}, nil
+}
@@ -0,0 +1,25 @@
+//go:build !windows && !plan9
+
+package processor
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID identifies a file independently of the path it is reached by.
+type fileID struct {
+	dev, ino uint64
+}
+
+func statID(path string) (fileID, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileID{}, err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, &os.PathError{Op: "stat", Path: path, Err: syscall.EINVAL}
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, nil
+}
@@ -0,0 +1,37 @@
+package processor
+
+import "strings"
+
+// Handling of hidden files and directories, whose names start with a dot.
+// By default only CI configuration and recognized file names are included.
+const (
+	HiddenDefault = "default"
+	HiddenInclude = "include"
+	HiddenExclude = "exclude"
+)
+
+// vcsDirs hold version control metadata and are never walked. Git worktrees
+// have a .git file instead.
+var vcsDirs = map[string]bool{".git": true, ".hg": true, ".svn": true}
+
+// ciConfigs are the hidden files and directories of CI services.
+var ciConfigs = map[string]bool{
+	".github": true, ".gitlab": true, ".gitlab-ci.yml": true, ".circleci": true,
+	".buildkite": true, ".travis.yml": true, ".drone.yml": true,
+	".woodpecker": true, ".woodpecker.yml": true, ".gitea": true, ".forgejo": true,
+}
+
+// isHiddenExcluded reports whether the hidden file or directory name is
+// excluded by opts.Hidden.
+func isHiddenExcluded(name string, isDir bool, opts Options) bool {
+	if !strings.HasPrefix(name, ".") {
+		return false
+	}
+	switch opts.Hidden {
+	case HiddenInclude:
+		return false
+	case HiddenExclude:
+		return true
+	}
+	return !ciConfigs[name] && (isDir || !opts.AllowedFileNames[name])
+}
@@ -0,0 +1,212 @@
+package processor
+
+import (
+	"code2md/importGraph"
+	"code2md/patternMatcher"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Orders of the files in the output.
+const (
+	OrderLexical    = "lexical"
+	OrderDirsFirst  = "dirs-first"
+	OrderFilesFirst = "files-first"
+	OrderSize       = "size"
+	OrderMtime      = "mtime"
+	OrderGit        = "git"
+	OrderDeps       = "deps"
+)
+
+// entry is a file or a summarized directory waiting to be written.
+type entry struct {
+	relPath string
+	isDir   bool
+	size    int64
+	modTime time.Time
+	render  func(w io.Writer) error
+}
+
+// sortEntries orders entries, which are in walk order, by the priority of
+// the first and last patterns they match and then by opts.Order. Ties keep
+// the walk order.
+func sortEntries(entries []*entry, opts Options) {
+	priority := make(map[*entry]int, len(entries))
+	for _, e := range entries {
+		priority[e] = orderPriority(e.relPath, opts.FirstPatterns, opts.LastPatterns)
+	}
+
+	less := orderLess(entries, opts)
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if priority[a] != priority[b] {
+			return priority[a] < priority[b]
+		}
+		return less != nil && less(a, b)
+	})
+}
+
+// orderPriority is negative for paths matching a first pattern, with the
+// first pattern lowest, zero for unmatched paths and positive for paths
+// matching a last pattern.
+func orderPriority(relPath string, first, last []patternMatcher.CompiledPattern) int {
+	for i := range first {
+		if patternMatcher.IsPathIgnored(relPath, first[i:i+1]) {
+			return i - len(first)
+		}
+	}
+	for i := range last {
+		if patternMatcher.IsPathIgnored(relPath, last[i:i+1]) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func orderLess(entries []*entry, opts Options) func(a, b *entry) bool {
+	switch opts.Order {
+	case OrderDirsFirst:
+		return func(a, b *entry) bool { return compareTree(a, b, true) }
+	case OrderFilesFirst:
+		return func(a, b *entry) bool { return compareTree(a, b, false) }
+	case OrderSize:
+		return func(a, b *entry) bool { return a.size < b.size }
+	case OrderMtime:
+		return func(a, b *entry) bool { return a.modTime.After(b.modTime) }
+	case OrderGit:
+		times, err := gitCommitTimes(opts.InputFolder)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ordering by walk order: %v\n", err)
+			return nil
+		}
+		commitTime := func(e *entry) int64 {
+			if t, ok := times[filepath.ToSlash(e.relPath)]; ok {
+				return t
+			}
+			return math.MaxInt64
+		}
+		return func(a, b *entry) bool { return commitTime(a) > commitTime(b) }
+	case OrderDeps:
+		rank, err := dependencyRanks(entries, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ordering by walk order: %v\n", err)
+			return nil
+		}
+		return func(a, b *entry) bool { return rank[filepath.Dir(a.relPath)] < rank[filepath.Dir(b.relPath)] }
+	}
+	return nil
+}
+
+// compareTree orders paths by directory, putting the subdirectories of a
+// directory before its files if dirsFirst is set and after them otherwise.
+func compareTree(a, b *entry, dirsFirst bool) bool {
+	pa := strings.Split(filepath.ToSlash(a.relPath), "/")
+	pb := strings.Split(filepath.ToSlash(b.relPath), "/")
+	for k := 0; k < len(pa) && k < len(pb); k++ {
+		if pa[k] == pb[k] {
+			continue
+		}
+		aDir := k < len(pa)-1 || a.isDir
+		bDir := k < len(pb)-1 || b.isDir
+		if aDir != bDir {
+			return aDir == dirsFirst
+		}
+		return pa[k] < pb[k]
+	}
+	return len(pa) < len(pb)
+}
+
+// gitCommitTimes returns the time of the last commit of every file below
+// root, keyed by its slash-separated path relative to root.
+func gitCommitTimes(root string) (map[string]int64, error) {
+	cmd := exec.Command("git", "-C", root, "-c", "core.quotePath=false", "log", "--format=%x00%ct", "--name-only", "--relative", "--no-renames")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading git history of %s: %w", root, err)
+	}
+
+	times := map[string]int64{}
+	var current int64
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "\x00") {
+			current, _ = strconv.ParseInt(line[1:], 10, 64)
+			continue
+		}
+		if _, ok := times[line]; line != "" && !ok {
+			times[line] = current
+		}
+	}
+	return times, nil
+}
+
+// dependencyRanks ranks the directories of entries so that Go packages come
+// before the packages of the same module importing them. Directories
+// without Go files and packages in an import cycle keep their walk order.
+func dependencyRanks(entries []*entry, opts Options) (map[string]int, error) {
+	module, err := importGraph.FindModule(opts.InputFolder)
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(opts.InputFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs, goFiles []string
+	relDirs := map[string]string{}
+	for _, e := range entries {
+		relDir := filepath.Dir(e.relPath)
+		dir := filepath.Join(root, relDir)
+		if _, ok := relDirs[dir]; !ok {
+			relDirs[dir] = relDir
+			dirs = append(dirs, dir)
+		}
+		if !e.isDir && filepath.Ext(e.relPath) == ".go" {
+			goFiles = append(goFiles, filepath.Join(root, e.relPath))
+		}
+	}
+
+	deps := module.Dependencies(goFiles)
+	order, cycles := importGraph.Sort(dirs, deps)
+	rank := make(map[string]int, len(order))
+	for i, dir := range order {
+		rank[relDirs[dir]] = i
+	}
+
+	if opts.Verbose {
+		fmt.Fprintf(os.Stderr, "Package order of module %s:\n", module.Path)
+		for _, dir := range order {
+			if _, ok := deps[dir]; !ok {
+				continue
+			}
+			var imports []string
+			for _, dep := range deps[dir] {
+				if relDir, ok := relDirs[dep]; ok {
+					imports = append(imports, relDir)
+				}
+			}
+			if len(imports) == 0 {
+				fmt.Fprintf(os.Stderr, "  %s\n", relDirs[dir])
+				continue
+			}
+			sort.Strings(imports)
+			fmt.Fprintf(os.Stderr, "  %s (imports %s)\n", relDirs[dir], strings.Join(imports, ", "))
+		}
+		for _, cycle := range cycles {
+			names := make([]string, len(cycle))
+			for i, dir := range cycle {
+				names[i] = relDirs[dir]
+			}
+			fmt.Fprintf(os.Stderr, "Import cycle, kept in walk order: %s\n", strings.Join(names, ", "))
+		}
+	}
+	return rank, nil
+}
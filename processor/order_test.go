@@ -0,0 +1,142 @@
+package processor
+
+import (
+	"code2md/patternMatcher"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func entriesFor(paths ...string) []*entry {
+	entries := make([]*entry, len(paths))
+	for i, p := range paths {
+		entries[i] = &entry{relPath: filepath.FromSlash(p)}
+	}
+	return entries
+}
+
+func entryPaths(entries []*entry) []string {
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = filepath.ToSlash(e.relPath)
+	}
+	return paths
+}
+
+func TestSortEntries(t *testing.T) {
+	walkOrder := []string{"a/x.go", "a.go", "b/c/y.go", "b/z.go", "main.go"}
+
+	tests := []struct {
+		name  string
+		order string
+		first []string
+		last  []string
+		want  []string
+	}{
+		{"lexical keeps walk order", OrderLexical, nil, nil, walkOrder},
+		{"dirs first", OrderDirsFirst, nil, nil, []string{"a/x.go", "b/c/y.go", "b/z.go", "a.go", "main.go"}},
+		{"files first", OrderFilesFirst, nil, nil, []string{"a.go", "main.go", "a/x.go", "b/z.go", "b/c/y.go"}},
+		{"first and last patterns", OrderLexical, []string{"main.go", "b/"}, []string{"a/"}, []string{"main.go", "b/c/y.go", "b/z.go", "a.go", "a/x.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := entriesFor(walkOrder...)
+			sortEntries(entries, Options{
+				Order:         tt.order,
+				FirstPatterns: patternMatcher.CompilePatterns(tt.first),
+				LastPatterns:  patternMatcher.CompilePatterns(tt.last),
+			})
+			if got := entryPaths(entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortEntries() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortEntriesBySizeAndTime(t *testing.T) {
+	now := time.Now()
+	entries := []*entry{
+		{relPath: "big", size: 300, modTime: now.Add(-time.Hour)},
+		{relPath: "small", size: 100, modTime: now.Add(-2 * time.Hour)},
+		{relPath: "medium", size: 200, modTime: now},
+		{relPath: "small2", size: 100, modTime: now.Add(-3 * time.Hour)},
+	}
+
+	sortEntries(entries, Options{Order: OrderSize})
+	if got, want := entryPaths(entries), []string{"small", "small2", "medium", "big"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("size order = %v; want %v", got, want)
+	}
+
+	sortEntries(entries, Options{Order: OrderMtime})
+	if got, want := entryPaths(entries), []string{"medium", "big", "small", "small2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mtime order = %v; want %v", got, want)
+	}
+}
+
+func TestSortEntriesByGitHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	gitCmd := func(env []string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), append([]string{"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com"}, env...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(file, date string) {
+		os.MkdirAll(filepath.Dir(filepath.Join(repo, file)), 0755)
+		os.WriteFile(filepath.Join(repo, file), []byte(file), 0644)
+		gitCmd(nil, "add", file)
+		gitCmd([]string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}, "commit", "-q", "-m", file)
+	}
+	gitCmd(nil, "init", "-q")
+	commit("old.go", "2020-01-01T00:00:00Z")
+	commit("sub/new.go", "2022-01-01T00:00:00Z")
+	commit("mid.go", "2021-01-01T00:00:00Z")
+	os.WriteFile(filepath.Join(repo, "untracked.go"), nil, 0644)
+
+	entries := entriesFor("mid.go", "old.go", "sub/new.go", "untracked.go")
+	sortEntries(entries, Options{InputFolder: repo, Order: OrderGit})
+	if got, want := entryPaths(entries), []string{"untracked.go", "sub/new.go", "mid.go", "old.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("git order = %v; want %v", got, want)
+	}
+
+	entries = entriesFor("new.go")
+	sortEntries(entries, Options{InputFolder: filepath.Join(repo, "sub"), Order: OrderGit})
+	if times, err := gitCommitTimes(filepath.Join(repo, "sub")); err != nil || len(times) != 1 || times["new.go"] == 0 {
+		t.Errorf("gitCommitTimes() of a subdirectory = %v, %v; want new.go only", times, err)
+	}
+}
+
+func TestSortEntriesByDependencies(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/app\n",
+		"README.md":        "# app\n",
+		"main.go":          "package main\n\nimport \"example.com/app/store\"\n",
+		"api/api.go":       "package api\n\nimport \"example.com/app/model\"\n",
+		"model/model.go":   "package model\n",
+		"store/store.go":   "package store\n\nimport (\n\t\"database/sql\"\n\t\"example.com/app/api\"\n)\n",
+		"store/schema.sql": "CREATE TABLE t (id int);\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries := entriesFor("README.md", "api/api.go", "go.mod", "main.go", "model/model.go", "store/schema.sql", "store/store.go")
+	sortEntries(entries, Options{InputFolder: root, Order: OrderDeps})
+	want := []string{"model/model.go", "api/api.go", "store/schema.sql", "store/store.go", "README.md", "go.mod", "main.go"}
+	if got := entryPaths(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("deps order = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// maxBytesInFlight bounds the memory held by files that are read or
+// rendered but not yet written.
+const maxBytesInFlight = 64 << 20
+
+// pipeline renders files on a pool of workers and writes the results in the
+// order they were submitted.
+type pipeline struct {
+	output  io.Writer
+	jobs    chan func()
+	pending chan *pendingOutput
+	budget  *byteBudget
+	workers sync.WaitGroup
+	written chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+type pendingOutput struct {
+	content  chan renderResult
+	reserved int64
+}
+
+type renderResult struct {
+	content []byte
+	err     error
+}
+
+// newPipeline starts jobs workers, or one per CPU if jobs is not positive,
+// and the writer.
+func newPipeline(output io.Writer, jobs int, maxInFlight int64) *pipeline {
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	p := &pipeline{
+		output:  output,
+		jobs:    make(chan func()),
+		pending: make(chan *pendingOutput, 16*jobs),
+		budget:  newByteBudget(maxInFlight),
+		written: make(chan struct{}),
+	}
+	for i := 0; i < jobs; i++ {
+		p.workers.Add(1)
+		go func() {
+			defer p.workers.Done()
+			for job := range p.jobs {
+				job()
+			}
+		}()
+	}
+	go p.write()
+	return p
+}
+
+// submit queues render, which is expected to produce about size bytes. It
+// blocks while too many bytes are in flight and returns the first error of
+// an earlier render or write, after which nothing more is written.
+func (p *pipeline) submit(size int64, render func(w io.Writer) error) error {
+	if err := p.failed(); err != nil {
+		return err
+	}
+	out := &pendingOutput{
+		content:  make(chan renderResult, 1),
+		reserved: p.budget.acquire(size),
+	}
+	p.pending <- out
+	p.jobs <- func() {
+		var buf bytes.Buffer
+		err := render(&buf)
+		out.content <- renderResult{buf.Bytes(), err}
+	}
+	return nil
+}
+
+// close waits for all submitted files to be written.
+func (p *pipeline) close() error {
+	close(p.pending)
+	close(p.jobs)
+	<-p.written
+	p.workers.Wait()
+	return p.failed()
+}
+
+func (p *pipeline) write() {
+	defer close(p.written)
+	for out := range p.pending {
+		result := <-out.content
+		if result.err == nil && p.failed() == nil {
+			if _, err := p.output.Write(result.content); err != nil {
+				result.err = fmt.Errorf("writing output: %w", err)
+			}
+		}
+		if result.err != nil {
+			p.fail(result.err)
+		}
+		p.budget.release(out.reserved)
+	}
+}
+
+func (p *pipeline) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *pipeline) failed() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// byteBudget is a semaphore counting bytes.
+type byteBudget struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	size      int64
+	available int64
+}
+
+func newByteBudget(size int64) *byteBudget {
+	b := &byteBudget{size: size, available: size}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// acquire reserves n bytes, at most the whole budget, and returns the
+// amount reserved.
+func (b *byteBudget) acquire(n int64) int64 {
+	if n > b.size {
+		n = b.size
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.available < n {
+		b.cond.Wait()
+	}
+	b.available -= n
+	return n
+}
+
+func (b *byteBudget) release(n int64) {
+	b.mu.Lock()
+	b.available += n
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
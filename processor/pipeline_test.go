@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPipelineKeepsOrder(t *testing.T) {
+	var output bytes.Buffer
+	p := newPipeline(&output, 8, 1024)
+
+	var want strings.Builder
+	for i := 0; i < 100; i++ {
+		i := i
+		fmt.Fprintf(&want, "%d\n", i)
+		err := p.submit(10, func(w io.Writer) error {
+			// Later files finish first.
+			time.Sleep(time.Duration(100-i) * 10 * time.Microsecond)
+			_, err := fmt.Fprintf(w, "%d\n", i)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("submit() error: %v", err)
+		}
+	}
+	if err := p.close(); err != nil {
+		t.Fatalf("close() error: %v", err)
+	}
+	if output.String() != want.String() {
+		t.Errorf("output = %q; want %q", output.String(), want.String())
+	}
+}
+
+func TestPipelineStopsAtFirstError(t *testing.T) {
+	var output bytes.Buffer
+	p := newPipeline(&output, 4, 1024)
+	failure := errors.New("boom")
+
+	var submitErr error
+	for i := 0; i < 1000 && submitErr == nil; i++ {
+		i := i
+		submitErr = p.submit(1, func(w io.Writer) error {
+			if i == 3 {
+				return failure
+			}
+			_, err := fmt.Fprintf(w, "%d\n", i)
+			return err
+		})
+		time.Sleep(time.Millisecond)
+	}
+	if err := p.close(); !errors.Is(err, failure) {
+		t.Errorf("close() error = %v; want %v", err, failure)
+	}
+	if !errors.Is(submitErr, failure) {
+		t.Errorf("submit() should report the failure, got %v", submitErr)
+	}
+	if output.String() != "0\n1\n2\n" {
+		t.Errorf("output = %q; want files before the failure only", output.String())
+	}
+}
+
+func TestPipelineBoundsBytesInFlight(t *testing.T) {
+	const budget = 100
+	var inFlight, peak int64
+	p := newPipeline(io.Discard, 8, budget)
+
+	for i := 0; i < 40; i++ {
+		err := p.submit(40, func(w io.Writer) error {
+			n := atomic.AddInt64(&inFlight, 40)
+			for {
+				old := atomic.LoadInt64(&peak)
+				if n <= old || atomic.CompareAndSwapInt64(&peak, old, n) {
+					break
+				}
+			}
+			time.Sleep(100 * time.Microsecond)
+			atomic.AddInt64(&inFlight, -40)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("submit() error: %v", err)
+		}
+	}
+	if err := p.close(); err != nil {
+		t.Fatalf("close() error: %v", err)
+	}
+	if peak > budget {
+		t.Errorf("peak bytes in flight = %d; want at most %d", peak, budget)
+	}
+}
+
+func TestByteBudgetCapsLargeRequests(t *testing.T) {
+	b := newByteBudget(10)
+	if got := b.acquire(50); got != 10 {
+		t.Errorf("acquire(50) = %d; want 10", got)
+	}
+	b.release(10)
+	if got := b.acquire(5); got != 5 {
+		t.Errorf("acquire(5) = %d; want 5", got)
+	}
+}
@@ -1,27 +1,136 @@
 package processor
 
 import (
+	"bytes"
+	"code2md/commentStripper"
+	"code2md/dumpParser"
+	"code2md/generatedCode"
+	"code2md/gitAttributes"
+	"code2md/importGraph"
 	"code2md/language"
+	"code2md/outline"
 	"code2md/patternMatcher"
+	"code2md/secretRedactor"
+	"code2md/sensitiveFiles"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 type Options struct {
-	InputFolder      string
-	AllowedLanguages map[string]bool
-	AllowedFileNames map[string]bool
-	IgnorePatterns   []patternMatcher.CompiledPattern
-	MaxFileSize      int64
+	InputFolder       string
+	AllowedLanguages  map[string]bool
+	AllowedFileNames  map[string]bool
+	IgnorePatterns    []patternMatcher.CompiledPattern
+	MaxFileSize       int64
+	Generated         string
+	StripComments     bool
+	StripLicense      bool
+	Outline           bool
+	FullPatterns      []patternMatcher.CompiledPattern
+	Redact            bool
+	FailOnSecrets     bool
+	SensitivePatterns []string
+	Jobs              int
+	Order             string
+	FirstPatterns     []patternMatcher.CompiledPattern
+	LastPatterns      []patternMatcher.CompiledPattern
+	MaxLines          int
+	TailLines         int
+	MaxBytesPerFile   int64
+	Hidden            string
+	FollowSymlinks    bool
+	NoOutsideSymlinks bool
+	FocusFiles        []string
+	FocusDepth        int
+	Verbose           bool
+	Stats             *Stats
 }
 
+// Stats summarizes a run of ProcessDirectory.
+type Stats struct {
+	Files     int
+	Bytes     int64
+	Sensitive []string
+	Truncated []Truncation
+}
+
+// Print writes the summary in a human readable form.
+func (s *Stats) Print(w io.Writer) {
+	fmt.Fprintf(w, "Files: %d\n", s.Files)
+	fmt.Fprintf(w, "Bytes: %d\n", s.Bytes)
+	if len(s.Sensitive) > 0 {
+		fmt.Fprintf(w, "Excluded as sensitive (%d):\n", len(s.Sensitive))
+		for _, path := range s.Sensitive {
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+	}
+	if len(s.Truncated) > 0 {
+		fmt.Fprintf(w, "Truncated (%d):\n", len(s.Truncated))
+		for _, t := range s.Truncated {
+			fmt.Fprintf(w, "  %s: %d lines omitted\n", t.Path, t.OmittedLines)
+		}
+	}
+}
+
+// Handling of generated and vendored files, excluded by default.
+const (
+	GeneratedExclude   = "exclude"
+	GeneratedSummarize = "summarize"
+	GeneratedInclude   = "include"
+)
+
 func ProcessDirectory(opts Options, output io.Writer) error {
-	found := false
+	stats := opts.Stats
+	if stats == nil {
+		stats = &Stats{}
+	}
+	counter := &countingWriter{w: output}
+	// files counts the files written in full, listed also those written as
+	// a summary.
+	files, listed := int64(0), int64(0)
+	var entries []*entry
+	attributes := gitAttributes.NewMatcher(opts.InputFolder)
+	elisions := contentElisions(opts)
+	fullOpts := opts
+	fullOpts.Outline = false
+	fullElisions := contentElisions(fullOpts)
+	secrets := &secretReport{}
+	truncations := &truncationReport{}
+	focus, err := focusSet(opts)
+	if err != nil {
+		return err
+	}
+
+	links, err := newSymlinks(opts)
+	if err != nil {
+		return err
+	}
 
-	ret := filepath.WalkDir(opts.InputFolder, func(path string, d os.DirEntry, err error) error {
+	// tree is the directory tree being walked. Trees of followed symlinks are
+	// displayed below relRoot and reached through the directories in chain.
+	type walkedTree struct {
+		root, relRoot string
+		chain         []fileID
+	}
+	tree := walkedTree{root: opts.InputFolder}
+	// excludeSensitive reports whether the file at relPath is sensitive and
+	// lists it in the stats.
+	excludeSensitive := func(relPath string) bool {
+		if !sensitiveFiles.IsSensitive(filepath.ToSlash(relPath), opts.SensitivePatterns) {
+			return false
+		}
+		stats.Sensitive = append(stats.Sensitive, relPath)
+		return true
+	}
+	var visit fs.WalkDirFunc
+	visit = func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			if os.IsPermission(err) {
 				fmt.Fprintf(os.Stderr, "Warning: permission denied: %s\n", path)
@@ -30,10 +139,11 @@ func ProcessDirectory(opts Options, output io.Writer) error {
 			return fmt.Errorf("accessing path %s: %w", path, err)
 		}
 
-		relPath, err := filepath.Rel(opts.InputFolder, path)
+		relPath, err := filepath.Rel(tree.root, path)
 		if err != nil {
 			return fmt.Errorf("getting relative path for %s: %w", path, err)
 		}
+		relPath = filepath.Join(tree.relRoot, relPath)
 
 		if patternMatcher.IsPathIgnored(relPath, opts.IgnorePatterns) {
 			if d.IsDir() {
@@ -44,76 +154,476 @@ func ProcessDirectory(opts Options, output io.Writer) error {
 			}
 		}
 
-		if !d.IsDir() && language.IsFileAllowed(d.Name(), opts.AllowedLanguages, opts.AllowedFileNames) {
-			found = true
-			lang := language.GetMarkdownLanguage(d.Name(), opts.AllowedFileNames)
-			return writeMarkdown(path, relPath, output, lang, opts.MaxFileSize)
+		if path == tree.root {
+			return nil
+		}
+		if vcsDirs[d.Name()] || isHiddenExcluded(d.Name(), d.IsDir(), opts) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			if focus == nil || focus[relPath] {
+				excludeSensitive(relPath)
+			}
+			return nil
+		}
+		if focus != nil && !d.IsDir() && !focus[relPath] {
+			return nil
+		}
+		attrs, err := attributes.Attributes(relPath)
+		if err != nil {
+			return err
+		}
+		if attrs.IsSet("export-ignore") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		isDir := d.IsDir()
+		var target string
+		var linkInfo os.FileInfo
+		if d.Type()&os.ModeSymlink != 0 {
+			if target, linkInfo = links.resolve(path); linkInfo == nil {
+				if excludeSensitive(relPath) {
+					return nil
+				}
+				return addSymlink(&entries, path, relPath, d, opts)
+			}
+			isDir = linkInfo.IsDir()
+		}
+
+		if isDir {
+			if opts.Generated == GeneratedInclude || !isVendoredDir(d.Name(), attrs) {
+				if linkInfo == nil {
+					return nil
+				}
+				ancestorIDs, err := ancestors(tree.root, path, tree.chain)
+				if err != nil {
+					return err
+				}
+				id, err := statID(target)
+				if err != nil {
+					return fmt.Errorf("stating directory %s: %w", target, err)
+				}
+				if containsID(ancestorIDs, id) {
+					fmt.Fprintf(os.Stderr, "Warning: not following symlink %s: it links to a parent directory\n", path)
+					if excludeSensitive(relPath) {
+						return nil
+					}
+					return addSymlink(&entries, path, relPath, d, opts)
+				}
+				parent := tree
+				tree = walkedTree{root: target, relRoot: relPath, chain: append(ancestorIDs, id)}
+				err = filepath.WalkDir(target, visit)
+				tree = parent
+				return err
+			}
+			if opts.Generated == GeneratedSummarize && focus == nil {
+				info, err := d.Info()
+				if err != nil {
+					return fmt.Errorf("stating directory %s: %w", path, err)
+				}
+				entries = append(entries, &entry{relPath: relPath, isDir: true, modTime: info.ModTime(), render: func(w io.Writer) error {
+					return writeSummary(w, relPath+string(filepath.Separator), "Vendored directory")
+				}})
+			}
+			if linkInfo != nil {
+				return nil
+			}
+			return filepath.SkipDir
+		}
+
+		if attrs.IsSet("binary") || attrs.IsUnset("diff") {
+			return nil
 		}
 
+		if excludeSensitive(relPath) {
+			return nil
+		}
+
+		lang, allowed, err := resolveLanguage(path, d.Name(), opts)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return nil
+		}
+		if name, ok := attrs["linguist-language"]; ok {
+			lang = language.FenceForLanguage(name)
+		}
+
+		fileElisions := elisions
+		if opts.Outline && patternMatcher.IsPathIgnored(relPath, opts.FullPatterns) {
+			fileElisions = fullElisions
+		}
+		// Secrets are detected before truncation, which could cut them in half.
+		var fileTransforms []Transform
+		if opts.Redact || opts.FailOnSecrets {
+			fileTransforms = append(fileTransforms, secrets.transform(relPath, opts.Redact))
+		}
+		if opts.MaxLines > 0 || opts.MaxBytesPerFile > 0 {
+			fileTransforms = append(fileTransforms, truncations.transform(relPath, opts))
+		}
+		info := linkInfo
+		if info == nil {
+			if info, err = d.Info(); err != nil {
+				return fmt.Errorf("stating file %s: %w", path, err)
+			}
+		}
+		entries = append(entries, &entry{relPath: relPath, size: info.Size(), modTime: info.ModTime(), render: func(w io.Writer) error {
+			if opts.Generated != GeneratedInclude {
+				kind, err := generatedKind(path, attrs)
+				if err != nil {
+					return err
+				}
+				if kind != "" {
+					if opts.Generated == GeneratedSummarize {
+						atomic.AddInt64(&listed, 1)
+						return writeSummary(w, relPath, kind+" file")
+					}
+					return nil
+				}
+			}
+			written, err := writeMarkdown(path, relPath, w, lang, opts.MaxFileSize, fileElisions, fileTransforms...)
+			if written {
+				atomic.AddInt64(&files, 1)
+				atomic.AddInt64(&listed, 1)
+			}
+			return err
+		}})
 		return nil
-	})
+	}
+
+	walkErr := filepath.WalkDir(opts.InputFolder, visit)
 
-	if ret != nil {
-		return ret
+	pipe := newPipeline(counter, opts.Jobs, maxBytesInFlight)
+	if walkErr == nil {
+		sortEntries(entries, opts)
+		for _, e := range entries {
+			if err := pipe.submit(e.size, e.render); err != nil {
+				break
+			}
+		}
+	}
+	pipeErr := pipe.close()
+	stats.Files += int(files)
+	stats.Bytes += counter.n
+	stats.Truncated = append(stats.Truncated, truncations.sorted()...)
+	if walkErr != nil {
+		return walkErr
+	}
+	if pipeErr != nil {
+		return pipeErr
 	}
 
-	if !found {
+	if listed == 0 {
 		return errors.New("no files processed - file list is empty")
 	}
 
+	if opts.FailOnSecrets && secrets.total > 0 {
+		return fmt.Errorf("found %d secrets in %d files", secrets.total, secrets.files)
+	}
+
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// secretReport counts the secrets found across files.
+type secretReport struct {
+	mu    sync.Mutex
+	total int
+	files int
+}
+
+// transform returns a Transform that detects secrets in the file at
+// displayPath, reports them to stderr and replaces them if redact is set.
+func (r *secretReport) transform(displayPath string, redact bool) Transform {
+	return func(content []byte, lang string) []byte {
+		redacted, counts := secretRedactor.Redact(content)
+		if len(counts) == 0 {
+			return content
+		}
+		r.mu.Lock()
+		r.total += secretRedactor.Total(counts)
+		r.files++
+		r.mu.Unlock()
+		if !redact {
+			fmt.Fprintf(os.Stderr, "Warning: possible secrets in %s: %s\n", displayPath, secretRedactor.Summary(counts))
+			return content
+		}
+		fmt.Fprintf(os.Stderr, "Warning: redacted secrets in %s: %s\n", displayPath, secretRedactor.Summary(counts))
+		return redacted
+	}
+}
+
+// focusSet returns the focus files and the files they import, relative to
+// the input folder, or nil without focus files. Focus files are looked up
+// in the input folder first and then in the working directory.
+func focusSet(opts Options) (map[string]bool, error) {
+	if len(opts.FocusFiles) == 0 {
+		return nil, nil
+	}
+	root, err := filepath.Abs(opts.InputFolder)
+	if err != nil {
+		return nil, err
+	}
+	starts := make([]string, len(opts.FocusFiles))
+	for i, file := range opts.FocusFiles {
+		if _, err := os.Stat(filepath.Join(root, file)); err == nil && !filepath.IsAbs(file) {
+			starts[i] = file
+			continue
+		}
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("focus file %s is not in %s", file, opts.InputFolder)
+		}
+		starts[i] = rel
+	}
+
+	focus, err := importGraph.NewResolver(root).Reachable(starts, opts.FocusDepth)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Verbose {
+		fmt.Fprintf(os.Stderr, "Focus: %d files reached from %s\n", len(focus), strings.Join(opts.FocusFiles, ", "))
+	}
+	return focus, nil
+}
+
+// addSymlink adds a link that is not followed as a one-line entry if it
+// links to a directory or its name is that of an allowed file.
+func addSymlink(entries *[]*entry, path, relPath string, d os.DirEntry, opts Options) error {
+	if info, err := os.Stat(path); (err != nil || !info.IsDir()) && !language.IsFileAllowed(d.Name(), opts.AllowedLanguages, opts.AllowedFileNames) {
+		return nil
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		return fmt.Errorf("reading symlink %s: %w", path, err)
+	}
+	info, err := d.Info()
+	if err != nil {
+		return fmt.Errorf("stating symlink %s: %w", path, err)
+	}
+	*entries = append(*entries, &entry{relPath: relPath, modTime: info.ModTime(), render: func(w io.Writer) error {
+		return writeSymlink(w, relPath, target)
+	}})
+	return nil
+}
+
+// resolveLanguage decides by name first and falls back to the shebang or
+// modeline of files without an extension.
+func resolveLanguage(path, name string, opts Options) (string, bool, error) {
+	if language.IsFileAllowed(name, opts.AllowedLanguages, opts.AllowedFileNames) {
+		return language.GetMarkdownLanguage(name, opts.AllowedFileNames), true, nil
+	}
+	if filepath.Ext(name) != "" {
+		return "", false, nil
+	}
+
+	ext, err := language.DetectFile(path)
+	if err != nil {
+		if os.IsPermission(err) {
+			fmt.Fprintf(os.Stderr, "Warning: permission denied: %s\n", path)
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("detecting language of %s: %w", path, err)
+	}
+	if ext == "" || !opts.AllowedLanguages[ext] {
+		return "", false, nil
+	}
+	return language.GetExtensionLanguage(ext), true, nil
+}
+
+// isVendoredDir recognizes common dependency directories unless
+// .gitattributes marks them with -linguist-vendored.
+func isVendoredDir(name string, attrs gitAttributes.Attributes) bool {
+	if attrs.IsSet("linguist-vendored") {
+		return true
+	}
+	if attrs.IsUnset("linguist-vendored") {
+		return false
+	}
+	return generatedCode.IsVendoredDir(name)
+}
+
+// generatedKind returns "Vendored" or "Generated" for files marked in
+// .gitattributes or carrying a generated code header, and "" otherwise.
+func generatedKind(path string, attrs gitAttributes.Attributes) (string, error) {
+	if attrs.IsSet("linguist-vendored") {
+		return "Vendored", nil
+	}
+	if attrs.IsSet("linguist-generated") {
+		return "Generated", nil
+	}
+	if attrs.IsUnset("linguist-generated") {
+		return "", nil
+	}
+
+	generated, err := generatedCode.IsGeneratedFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading file %s: %w", path, err)
+	}
+	if generated {
+		return "Generated", nil
+	}
+	return "", nil
+}
+
+func writeSummary(output io.Writer, displayPath, kind string) error {
+	if _, err := fmt.Fprintf(output, "# %s\n\n_%s, content omitted._\n\n", displayPath, kind); err != nil {
+		return fmt.Errorf("writing summary for %s: %w", displayPath, err)
+	}
 	return nil
 }
 
-func writeMarkdown(path string, displayPath string, output io.Writer, lang string, maxFileSize int64) error {
+// Transform rewrites the content of a file written in the fence language lang
+// before it is written to the output.
+type Transform func(content []byte, lang string) []byte
+
+// contentElisions returns the transforms that leave out parts of files:
+// outlines and stripped comments. Blocks they change are marked as elided
+// after the fence language, so that apply refuses them.
+func contentElisions(opts Options) []Transform {
+	var transforms []Transform
+	if opts.Outline {
+		transforms = append(transforms, outlineTransform)
+	}
+	if opts.StripComments {
+		transforms = append(transforms, commentStripper.Strip)
+	} else if opts.StripLicense {
+		transforms = append(transforms, commentStripper.StripLicense)
+	}
+	return transforms
+}
+
+// outlineTransform replaces content with its outline, keeping the full
+// content of unsupported languages and of files that fail to parse.
+func outlineTransform(content []byte, lang string) []byte {
+	if !outline.IsSupported(lang) {
+		return content
+	}
+	rendered, err := outline.Render(content, lang)
+	if err != nil {
+		return content
+	}
+	return rendered
+}
+
+// writeMarkdown writes the file at path as a block and reports whether it
+// was written, which it is not when larger than maxFileSize.
+func writeMarkdown(path string, displayPath string, output io.Writer, lang string, maxFileSize int64, elisions []Transform, transforms ...Transform) (bool, error) {
 	fileInfo, err := os.Stat(path)
 	if err != nil {
-		return fmt.Errorf("stating file %s: %w", path, err)
+		return false, fmt.Errorf("stating file %s: %w", path, err)
 	}
 
 	if fileInfo.Size() > maxFileSize {
 		fmt.Fprintf(os.Stderr, "Warning: skipping large file %s (%d bytes)\n", displayPath, fileInfo.Size())
-		return nil
+		return false, nil
+	}
+
+	var content []byte
+	elided := false
+	if len(elisions) > 0 || len(transforms) > 0 {
+		if content, elided, err = transformContent(path, lang, elisions, transforms); err != nil {
+			return false, err
+		}
 	}
 
 	if _, err := io.WriteString(output, "# "+displayPath+"\n"); err != nil {
-		return fmt.Errorf("writing header for %s: %w", path, err)
+		return false, fmt.Errorf("writing header for %s: %w", path, err)
 	}
 
 	if lang != "md" {
-		if _, err := io.WriteString(output, "```"+lang+"\n"); err != nil {
-			return fmt.Errorf("writing header for %s: %w", path, err)
+		fence := "```" + lang
+		if elided {
+			fence += " " + dumpParser.Elided
+		}
+		if _, err := io.WriteString(output, fence+"\n"); err != nil {
+			return false, fmt.Errorf("writing header for %s: %w", path, err)
 		}
 	}
 
-	file, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("reading file %s: %w", path, err)
+	var endsWithNewline bool
+	if len(elisions) == 0 && len(transforms) == 0 {
+		endsWithNewline, err = copyContent(path, output, fileInfo.Size())
+	} else if _, err = output.Write(content); err != nil {
+		err = fmt.Errorf("writing content from %s: %w", path, err)
+	} else {
+		endsWithNewline = len(content) > 0 && content[len(content)-1] == '\n'
 	}
-	defer file.Close()
-
-	if _, err := io.Copy(output, file); err != nil {
-		return fmt.Errorf("writing content from %s: %w", path, err)
+	if err != nil {
+		return false, err
 	}
 
+	// Markdown is not fenced and always ends with two blank lines, which
+	// tells the next header apart from its own headings.
 	suffix := ""
+	if !endsWithNewline {
+		suffix = "\n"
+	}
 	if lang != "md" {
-		needsNewline := true
-		if fileInfo.Size() > 0 {
-			lastByte := make([]byte, 1)
-			if _, err := file.ReadAt(lastByte, fileInfo.Size()-1); err == nil {
-				needsNewline = lastByte[0] != '\n'
-			}
-		}
-		if needsNewline {
-			suffix = "\n"
-		}
 		suffix += "```"
 	}
 	suffix += "\n\n"
 
 	if _, err := io.WriteString(output, suffix); err != nil {
-		return fmt.Errorf("writing suffix for %s: %w", path, err)
+		return false, fmt.Errorf("writing suffix for %s: %w", path, err)
 	}
 
-	return nil
+	return true, nil
+}
+
+func copyContent(path string, output io.Writer, size int64) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("reading file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(output, file); err != nil {
+		return false, fmt.Errorf("writing content from %s: %w", path, err)
+	}
+
+	if size == 0 {
+		return false, nil
+	}
+	lastByte := make([]byte, 1)
+	if _, err := file.ReadAt(lastByte, size-1); err != nil {
+		return false, nil
+	}
+	return lastByte[0] == '\n', nil
+}
+
+// transformContent reads the file at path and applies the elisions and then
+// the transforms. It reports whether the elisions changed the content.
+func transformContent(path, lang string, elisions, transforms []Transform) ([]byte, bool, error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading file %s: %w", path, err)
+	}
+	content := original
+	for _, elision := range elisions {
+		content = elision(content, lang)
+	}
+	elided := !bytes.Equal(content, original)
+	for _, transform := range transforms {
+		content = transform(content, lang)
+	}
+	return content, elided, nil
 }
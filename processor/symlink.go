@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// symlinks decides which symbolic links are followed during the walk.
+type symlinks struct {
+	follow    bool
+	noOutside bool
+	realRoot  string
+}
+
+func newSymlinks(opts Options) (*symlinks, error) {
+	s := &symlinks{follow: opts.FollowSymlinks, noOutside: opts.NoOutsideSymlinks}
+	if s.follow && s.noOutside {
+		root, err := filepath.Abs(opts.InputFolder)
+		if err == nil {
+			root, err = filepath.EvalSymlinks(root)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("resolving input folder %s: %w", opts.InputFolder, err)
+		}
+		s.realRoot = root
+	}
+	return s, nil
+}
+
+// resolve returns the target of the link at path and its file info if the
+// link is followed, or nil info otherwise.
+func (s *symlinks) resolve(path string) (string, os.FileInfo) {
+	if !s.follow {
+		return "", nil
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: not following symlink %s: %v\n", path, err)
+		return "", nil
+	}
+	if s.noOutside {
+		abs, err := filepath.Abs(target)
+		if err != nil || !isWithin(s.realRoot, abs) {
+			fmt.Fprintf(os.Stderr, "Warning: not following symlink %s: target outside the input folder\n", path)
+			return "", nil
+		}
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: not following symlink %s: %v\n", path, err)
+		return "", nil
+	}
+	return target, info
+}
+
+// ancestors appends the identities of the directories from path up to
+// root, the directory the walk containing path started at, to chain.
+func ancestors(root, path string, chain []fileID) ([]fileID, error) {
+	root = filepath.Clean(root)
+	chain = chain[:len(chain):len(chain)]
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		id, err := statID(dir)
+		if err != nil {
+			return nil, fmt.Errorf("stating directory %s: %w", dir, err)
+		}
+		chain = append(chain, id)
+		if dir == root || filepath.Dir(dir) == dir {
+			return chain, nil
+		}
+	}
+}
+
+func containsID(chain []fileID, id fileID) bool {
+	for _, c := range chain {
+		if c == id {
+			return true
+		}
+	}
+	return false
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func writeSymlink(output io.Writer, displayPath, target string) error {
+	if _, err := fmt.Fprintf(output, "# %s\n\n_symlink → %s_\n\n", displayPath, filepath.ToSlash(target)); err != nil {
+		return fmt.Errorf("writing symlink %s: %w", displayPath, err)
+	}
+	return nil
+}
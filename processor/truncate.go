@@ -0,0 +1,106 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Truncation records a file of which only the first and last lines were
+// written.
+type Truncation struct {
+	Path         string
+	OmittedLines int
+}
+
+// truncationReport collects the files truncated across workers.
+type truncationReport struct {
+	mu    sync.Mutex
+	files []Truncation
+}
+
+// transform returns a Transform that truncates the file at displayPath to
+// opts.MaxLines and opts.MaxBytesPerFile, keeping opts.TailLines lines at
+// the end.
+func (r *truncationReport) transform(displayPath string, opts Options) Transform {
+	return func(content []byte, lang string) []byte {
+		truncated, omitted := truncate(content, opts.MaxLines, opts.TailLines, opts.MaxBytesPerFile)
+		if omitted > 0 {
+			r.mu.Lock()
+			r.files = append(r.files, Truncation{Path: displayPath, OmittedLines: omitted})
+			r.mu.Unlock()
+		}
+		return truncated
+	}
+}
+
+// sorted returns the truncations ordered by path.
+func (r *truncationReport) sorted() []Truncation {
+	sort.Slice(r.files, func(i, j int) bool { return r.files[i].Path < r.files[j].Path })
+	return r.files
+}
+
+// truncate keeps the first lines of content and its last tailLines lines,
+// at most maxLines lines in front and maxBytes bytes in total. Limits that
+// are not positive do not apply. The omitted lines are replaced with a
+// marker line; their number is returned.
+func truncate(content []byte, maxLines, tailLines int, maxBytes int64) ([]byte, int) {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	n := len(lines)
+	if tailLines < 0 {
+		tailLines = 0
+	}
+
+	head, tail := n, 0
+	if maxLines > 0 && n > maxLines+tailLines {
+		head, tail = maxLines, tailLines
+	}
+	if maxBytes > 0 && size(lines[:head])+size(lines[n-tail:]) > maxBytes {
+		if tail == 0 && tailLines < n {
+			tail = tailLines
+		}
+		for tail > 0 && size(lines[n-tail:]) > maxBytes/2 {
+			tail--
+		}
+		budget := maxBytes - size(lines[n-tail:])
+		limit := head
+		if limit > n-tail {
+			limit = n - tail
+		}
+		head = 0
+		for head < limit && int64(len(lines[head])) <= budget {
+			budget -= int64(len(lines[head]))
+			head++
+		}
+	}
+	if head+tail >= n {
+		return content, 0
+	}
+
+	omitted := n - head - tail
+	var out bytes.Buffer
+	for _, line := range lines[:head] {
+		out.Write(line)
+	}
+	if omitted == 1 {
+		out.WriteString("… 1 line omitted …\n")
+	} else {
+		fmt.Fprintf(&out, "… %d lines omitted …\n", omitted)
+	}
+	for _, line := range lines[n-tail:] {
+		out.Write(line)
+	}
+	return out.Bytes(), omitted
+}
+
+func size(lines [][]byte) int64 {
+	var n int64
+	for _, line := range lines {
+		n += int64(len(line))
+	}
+	return n
+}
@@ -0,0 +1,50 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		b.WriteString(strings.Repeat("x", 9) + string(rune('0'+i%10)) + "\n")
+	}
+	return b.String()
+}
+
+func TestTruncate(t *testing.T) {
+	ten := numberedLines(10)
+	lines := strings.SplitAfter(ten, "\n")
+
+	tests := []struct {
+		name        string
+		content     string
+		maxLines    int
+		tailLines   int
+		maxBytes    int64
+		want        string
+		wantOmitted int
+	}{
+		{"no limits", ten, 0, 0, 0, ten, 0},
+		{"short enough", ten, 10, 0, 0, ten, 0},
+		{"head only", ten, 3, 0, 0, strings.Join(lines[:3], "") + "… 7 lines omitted …\n", 7},
+		{"head and tail", ten, 3, 2, 0, strings.Join(lines[:3], "") + "… 5 lines omitted …\n" + strings.Join(lines[8:], ""), 5},
+		{"tail covers the rest", ten, 8, 2, 0, ten, 0},
+		{"single omitted line", ten, 7, 2, 0, strings.Join(lines[:7], "") + "… 1 line omitted …\n" + strings.Join(lines[8:], ""), 1},
+		{"bytes", ten, 0, 0, 35, strings.Join(lines[:3], "") + "… 7 lines omitted …\n", 7},
+		{"bytes with tail", ten, 0, 1, 35, strings.Join(lines[:2], "") + "… 7 lines omitted …\n" + lines[9], 7},
+		{"bytes below line limit", ten, 5, 0, 22, strings.Join(lines[:2], "") + "… 8 lines omitted …\n", 8},
+		{"no trailing newline", "a\nb\nc", 1, 1, 0, "a\n… 1 line omitted …\nc", 1},
+		{"empty", "", 1, 0, 1, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, omitted := truncate([]byte(tt.content), tt.maxLines, tt.tailLines, tt.maxBytes)
+			if string(got) != tt.want || omitted != tt.wantOmitted {
+				t.Errorf("truncate() = %q, %d; want %q, %d", got, omitted, tt.want, tt.wantOmitted)
+			}
+		})
+	}
+}
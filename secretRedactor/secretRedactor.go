@@ -0,0 +1,158 @@
+package secretRedactor
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of detected secrets.
+const (
+	PrivateKey     = "private-key"
+	AWSAccessKey   = "aws-access-key"
+	JWT            = "jwt"
+	GitHubToken    = "github-token"
+	SecretAssigned = "secret-assignment"
+)
+
+// minEntropy is the Shannon entropy in bits per character above which an
+// assigned value is taken for a generated key rather than a word.
+const minEntropy = 3.5
+
+type detector struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+var detectors = []detector{
+	{PrivateKey, regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?-----.*?-----END [A-Z0-9 ]*-----`)},
+	{JWT, regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{AWSAccessKey, regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	{GitHubToken, regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+}
+
+// quotedAssignment matches quoted values assigned to names ending in key,
+// secret or token in source code, JSON, YAML and PHP arrays. The third group
+// is the value.
+var quotedAssignment = regexp.MustCompile(`(?i)([a-z0-9_.-]*(?:key|secret|token))["']?\s*(?::=|=>|=|:)\s*(["'])([a-z0-9+/=_-]{16,})["']`)
+
+// lineAssignment matches unquoted values that make up the rest of a line
+// in .env files and YAML, such as API_KEY=value or api_key: value. Values
+// followed by a call or a field access are code. The second group is the
+// value.
+var lineAssignment = regexp.MustCompile(`(?im)^[ \t]*(?:export[ \t]+)?([a-z0-9_.-]*(?:key|secret|token))[ \t]*(?:=|:[ \t])[ \t]*([a-z0-9+/=_-]{16,})[ \t]*(?:#.*)?\r?$`)
+
+// identifier matches names such as user_profile_settings_v2, CACHE-KEY-2 or
+// defaultSortOrderKey: words of one case with trailing digits, joined by
+// underscores or dashes, or camel case words.
+var identifier = regexp.MustCompile(`^(?:[a-z]+[0-9]*|[0-9]+)(?:[_-](?:[a-z]+[0-9]*|[0-9]+))+$|^(?:[A-Z]+[0-9]*|[0-9]+)(?:[_-](?:[A-Z]+[0-9]*|[0-9]+))+$|^[a-z]+[0-9]*(?:[A-Z][a-z]+[0-9]*)+$`)
+
+// Placeholder returns the text that replaces a secret of the given kind.
+func Placeholder(kind string) string {
+	return "[REDACTED:" + kind + "]"
+}
+
+// Redact replaces private keys, AWS access keys, JWTs, GitHub tokens and
+// high-entropy values assigned to *_KEY, *_SECRET and *_TOKEN names with
+// placeholders. It returns the redacted content and the number of secrets
+// found by kind.
+func Redact(content []byte) ([]byte, map[string]int) {
+	counts := map[string]int{}
+	for _, d := range detectors {
+		content = d.pattern.ReplaceAllFunc(content, func(match []byte) []byte {
+			counts[d.kind]++
+			return []byte(Placeholder(d.kind))
+		})
+	}
+
+	isSecret := func(value []byte) bool {
+		if identifier.Match(value) || !isHighEntropy(string(value)) {
+			return false
+		}
+		counts[SecretAssigned]++
+		return true
+	}
+	content = replaceSubmatch(content, quotedAssignment, 3, isSecret, Placeholder(SecretAssigned))
+	content = replaceSubmatch(content, lineAssignment, 2, isSecret, Placeholder(SecretAssigned))
+
+	if len(counts) == 0 {
+		return content, nil
+	}
+	return content, counts
+}
+
+// replaceSubmatch replaces the given submatch of every match of re with
+// replacement when keep reports true for it.
+func replaceSubmatch(content []byte, re *regexp.Regexp, group int, keep func([]byte) bool, replacement string) []byte {
+	matches := re.FindAllSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content
+	}
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*group], m[2*group+1]
+		if start < 0 || !keep(content[start:end]) {
+			continue
+		}
+		out = append(out, content[last:start]...)
+		out = append(out, replacement...)
+		last = end
+	}
+	if out == nil {
+		return content
+	}
+	return append(out, content[last:]...)
+}
+
+// isHighEntropy reports whether value looks generated: it mixes at least
+// two of lower case letters, upper case letters and digits and its entropy
+// is above minEntropy.
+func isHighEntropy(value string) bool {
+	classes := 0
+	for _, class := range []string{"abcdefghijklmnopqrstuvwxyz", "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "0123456789"} {
+		if strings.ContainsAny(value, class) {
+			classes++
+		}
+	}
+	return classes >= 2 && entropy(value) >= minEntropy
+}
+
+func entropy(value string) float64 {
+	frequencies := map[rune]float64{}
+	for _, r := range value {
+		frequencies[r]++
+	}
+	var h float64
+	n := float64(len(value))
+	for _, count := range frequencies {
+		p := count / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// Summary formats counts as "2 aws-access-key, 1 jwt".
+func Summary(counts map[string]int) string {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", counts[kind], kind)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Total returns the number of secrets in counts.
+func Total(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
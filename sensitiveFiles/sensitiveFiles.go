@@ -0,0 +1,57 @@
+package sensitiveFiles
+
+import (
+	"path"
+	"strings"
+)
+
+// DefaultToken stands for DefaultPatterns in a pattern list.
+const DefaultToken = "defaults"
+
+// DefaultPatterns match files that commonly hold credentials. Patterns
+// starting with ! exempt files matched by earlier patterns.
+var DefaultPatterns = []string{
+	".env", ".env.*", "!.env.example", "!.env.sample", "!.env.template", "!.env.dist",
+	"*.pem", "*.key", "*.p12", "*.pfx", "*.jks", "*.keystore", "*.kdbx",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519",
+	"credentials", "credentials.json", "client_secret*.json",
+	"kubeconfig", "*.kubeconfig",
+	".npmrc", ".pypirc", ".netrc", ".pgpass", ".htpasswd", ".git-credentials",
+	"secrets.yml", "secrets.yaml", "secrets.json",
+	"*.tfstate", "*.tfstate.backup",
+}
+
+// Patterns splits a comma-separated pattern list, expanding DefaultToken.
+func Patterns(list string) []string {
+	var patterns []string
+	for _, p := range strings.Split(list, ",") {
+		p = strings.TrimSpace(p)
+		switch p {
+		case "":
+		case DefaultToken:
+			patterns = append(patterns, DefaultPatterns...)
+		default:
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// IsSensitive reports whether the file at the slash-separated relPath
+// matches patterns. Patterns without a slash match the file name, the
+// others the whole path; the last matching pattern decides.
+func IsSensitive(relPath string, patterns []string) bool {
+	sensitive := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		subject := path.Base(relPath)
+		if strings.Contains(pattern, "/") {
+			subject = relPath
+		}
+		if matched, _ := path.Match(pattern, subject); matched {
+			sensitive = !negated
+		}
+	}
+	return sensitive
+}
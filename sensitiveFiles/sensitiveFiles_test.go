@@ -0,0 +1,60 @@
+package sensitiveFiles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsSensitive(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{".env", true},
+		{"config/.env.production", true},
+		{".env.example", false},
+		{"certs/server.pem", true},
+		{"home/.ssh/id_rsa", true},
+		{"home/.ssh/id_rsa.pub", false},
+		{"deploy/credentials.json", true},
+		{"auth/credentials.go", false},
+		{"kubeconfig", true},
+		{"main.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := IsSensitive(tt.path, DefaultPatterns); got != tt.want {
+				t.Errorf("IsSensitive(%q) = %v; want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSensitiveCustomPatterns(t *testing.T) {
+	patterns := Patterns("defaults, !*.pem, config/local.json")
+	if IsSensitive("certs/server.pem", patterns) {
+		t.Error("!*.pem should exempt pem files")
+	}
+	if !IsSensitive("config/local.json", patterns) {
+		t.Error("patterns with a slash should match the whole path")
+	}
+	if IsSensitive("other/config/local.json", patterns) {
+		t.Error("patterns with a slash should not match deeper paths")
+	}
+	if !IsSensitive(".env", patterns) {
+		t.Error("defaults should be expanded")
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	if got := Patterns(""); got != nil {
+		t.Errorf("Patterns(\"\") = %v; want nil", got)
+	}
+	if got, want := Patterns(" a , ,b"), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Patterns() = %v; want %v", got, want)
+	}
+	if got := Patterns("defaults"); len(got) != len(DefaultPatterns) {
+		t.Errorf("Patterns(\"defaults\") has %d patterns; want %d", len(got), len(DefaultPatterns))
+	}
+}